@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hibiken/asynq"
+)
+
+// defaultAsynqQueue is the queue used by asynq when Config.Queues is empty.
+const defaultAsynqQueue = "default"
+
+// TaskHandler is a handler of task type with the queue which the task is enqueued to.
+type TaskHandler struct {
+	TaskType string            // Task type registered on ServeMux
+	Queue    string            // Queue which the task is enqueued to (empty: default queue)
+	Handler  asynq.HandlerFunc // Handler of the task
+}
+
+// AsynqServerConfig describes a worker server with the task handlers it registers.
+// Handlers is the single registration table, it feeds both NewServeMux and ValidateAsynqConfig.
+type AsynqServerConfig struct {
+	Name     string        // Name of server instance, used in validation messages
+	Config   asynq.Config  // Config passed to asynq.NewServer
+	Handlers []TaskHandler // Task handlers registered on ServeMux of the server
+}
+
+// NewServeMux returns a ServeMux with the given middlewares and all handlers of the server registered.
+//
+// Example:
+//
+//	srv.Run(serverConfig.NewServeMux(otel.AsynqMiddleware(observer, "asynq_task_processing_latency_ms")))
+func (serverConfig *AsynqServerConfig) NewServeMux(middlewares ...asynq.MiddlewareFunc) *asynq.ServeMux {
+	mux := asynq.NewServeMux()
+	mux.Use(middlewares...)
+	for _, taskHandler := range serverConfig.Handlers {
+		mux.HandleFunc(taskHandler.TaskType, taskHandler.Handler)
+	}
+	return mux
+}
+
+// queues returns the queues processed by the server with their priority.
+func (serverConfig *AsynqServerConfig) queues() map[string]int {
+	if len(serverConfig.Config.Queues) == 0 {
+		return map[string]int{defaultAsynqQueue: 1}
+	}
+	return serverConfig.Config.Queues
+}
+
+// ValidateAsynqConfig checks that every registered handler's queue is declared in Queues of its server
+// and no task type is registered twice on a server (ServeMux panics on it).
+// Servers binding the same queue with conflicting concurrency or priority are only warned,
+// because running many instances on one queue is valid but usually looks like duplicate processing.
+//
+// Example:
+//
+//	if err := ValidateAsynqConfig(workerConfig1, workerConfig2); err != nil {
+//		log.Fatal(err)
+//	}
+func ValidateAsynqConfig(serverConfigs ...AsynqServerConfig) error {
+	errs := []string{}
+
+	for _, serverConfig := range serverConfigs {
+		queues := serverConfig.queues()
+
+		for queue, priority := range queues {
+			if priority <= 0 {
+				errs = append(errs, fmt.Sprintf("server '%s': queue '%s' has non-positive priority %d", serverConfig.Name, queue, priority))
+			}
+		}
+
+		taskTypes := make(map[string]struct{}, len(serverConfig.Handlers))
+		for _, taskHandler := range serverConfig.Handlers {
+			if _, ok := taskTypes[taskHandler.TaskType]; ok {
+				errs = append(errs, fmt.Sprintf("server '%s': handler '%s' is registered more than once", serverConfig.Name, taskHandler.TaskType))
+			}
+			taskTypes[taskHandler.TaskType] = struct{}{}
+
+			queue := taskHandler.Queue
+			if queue == "" {
+				queue = defaultAsynqQueue
+			}
+			if _, ok := queues[queue]; !ok {
+				errs = append(errs, fmt.Sprintf("server '%s': handler '%s' uses queue '%s' which is not declared in Queues", serverConfig.Name, taskHandler.TaskType, queue))
+			}
+		}
+	}
+
+	// Warn on servers binding the same queue with conflicting settings
+	for i := 0; i < len(serverConfigs); i++ {
+		for j := i + 1; j < len(serverConfigs); j++ {
+			serverConfig1, serverConfig2 := serverConfigs[i], serverConfigs[j]
+			queues1, queues2 := serverConfig1.queues(), serverConfig2.queues()
+
+			for queue, priority1 := range queues1 {
+				priority2, ok := queues2[queue]
+				if !ok {
+					continue
+				}
+				if priority1 != priority2 || serverConfig1.Config.Concurrency != serverConfig2.Config.Concurrency {
+					log.Printf("[warning] Servers '%s' (concurrency: %d, priority: %d) and '%s' (concurrency: %d, priority: %d) bind the same queue '%s' with conflicting settings",
+						serverConfig1.Name, serverConfig1.Config.Concurrency, priority1,
+						serverConfig2.Name, serverConfig2.Config.Concurrency, priority2,
+						queue)
+				}
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("invalid asynq config: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
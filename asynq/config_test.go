@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hibiken/asynq"
+)
+
+func noopTaskHandler(ctx context.Context, t *asynq.Task) error { return nil }
+
+func TestValidateAsynqConfig(t *testing.T) {
+	testCases := []struct {
+		name         string
+		serverConfig AsynqServerConfig
+		expectedErr  string
+	}{
+		{
+			name: "handler queue declared",
+			serverConfig: AsynqServerConfig{
+				Name:     "worker",
+				Config:   asynq.Config{Queues: map[string]int{"mytask": 3}},
+				Handlers: []TaskHandler{{TaskType: "myqueuetask:hello", Queue: "mytask", Handler: noopTaskHandler}},
+			},
+		},
+		{
+			name: "empty queue uses default queue",
+			serverConfig: AsynqServerConfig{
+				Name:     "worker",
+				Handlers: []TaskHandler{{TaskType: "myqueuetask:hello", Handler: noopTaskHandler}},
+			},
+		},
+		{
+			name: "handler queue not declared",
+			serverConfig: AsynqServerConfig{
+				Name:     "worker",
+				Config:   asynq.Config{Queues: map[string]int{"mytask": 3}},
+				Handlers: []TaskHandler{{TaskType: "myqueuetask:hello", Queue: "other", Handler: noopTaskHandler}},
+			},
+			expectedErr: "handler 'myqueuetask:hello' uses queue 'other' which is not declared in Queues",
+		},
+		{
+			name: "task type registered twice",
+			serverConfig: AsynqServerConfig{
+				Name:   "worker",
+				Config: asynq.Config{Queues: map[string]int{"mytask": 3}},
+				Handlers: []TaskHandler{
+					{TaskType: "myqueuetask:hello", Queue: "mytask", Handler: noopTaskHandler},
+					{TaskType: "myqueuetask:hello", Queue: "mytask", Handler: noopTaskHandler},
+				},
+			},
+			expectedErr: "handler 'myqueuetask:hello' is registered more than once",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := ValidateAsynqConfig(testCase.serverConfig)
+			if testCase.expectedErr == "" {
+				if err != nil {
+					t.Errorf("ValidateAsynqConfig = %v, expected nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedErr) {
+				t.Errorf("ValidateAsynqConfig = %v, expected error containing %q", err, testCase.expectedErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EnqueueResult is the tracked result of an enqueued task.
+// It can be stored to query task status later via asynq.Inspector.
+type EnqueueResult struct {
+	TaskID      string          `json:"task_id"`      // ID of enqueued task
+	TaskType    string          `json:"task_type"`    // Type name of enqueued task
+	Queue       string          `json:"queue"`        // Queue which the task is enqueued to
+	State       asynq.TaskState `json:"state"`        // State of task right after enqueuing (pending, scheduled, ...)
+	ScheduledAt time.Time       `json:"scheduled_at"` // Time which the task is processed at
+	TraceID     string          `json:"trace_id"`     // Trace ID of originating span (empty if no span in context)
+	SpanID      string          `json:"span_id"`      // Span ID of originating span (empty if no span in context)
+}
+
+// EnqueueTracked enqueues the task and records the task info as attributes of the span in context.
+//
+// Example:
+//
+//	result, err := EnqueueTracked(ctx, client, asynq.NewTask("myqueuetask:hello", payload, asynq.Queue("mytask")))
+//	...
+//	taskInfo, err := result.GetTaskInfo(inspector)
+func EnqueueTracked(ctx context.Context, client *asynq.Client, task *asynq.Task, opts ...asynq.Option) (*EnqueueResult, error) {
+	span := trace.SpanFromContext(ctx)
+
+	taskInfo, err := client.EnqueueContext(ctx, task, opts...)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.String("asynq.task_id", taskInfo.ID),
+		attribute.String("asynq.task_type", taskInfo.Type),
+		attribute.String("asynq.queue", taskInfo.Queue),
+	)
+
+	result := &EnqueueResult{
+		TaskID:      taskInfo.ID,
+		TaskType:    taskInfo.Type,
+		Queue:       taskInfo.Queue,
+		State:       taskInfo.State,
+		ScheduledAt: taskInfo.NextProcessAt,
+	}
+	if spanCtx := span.SpanContext(); spanCtx.IsValid() {
+		result.TraceID = spanCtx.TraceID().String()
+		result.SpanID = spanCtx.SpanID().String()
+	}
+
+	return result, nil
+}
+
+// GetTaskInfo queries current info of the tracked task.
+func (result *EnqueueResult) GetTaskInfo(inspector *asynq.Inspector) (*asynq.TaskInfo, error) {
+	return inspector.GetTaskInfo(result.Queue, result.TaskID)
+}
@@ -34,11 +34,10 @@ asynq:{<queue>}:failed: Chứa số lượng task đã xử lý thất bại
 */
 
 func main() {
-
 	go func() {
-		srv := asynq.NewServer(
-			asynq.RedisClientOpt{Addr: "127.0.0.1:6379", Password: "12345678", DB: 1},
-			asynq.Config{
+		serverConfig := AsynqServerConfig{
+			Name: "worker",
+			Config: asynq.Config{
 				Concurrency: 3, // distributed worker = chạy nhiều instance
 				Queues: map[string]int{
 					"mytask": 3,
@@ -47,35 +46,51 @@ func main() {
 					return 5 * time.Second
 				},
 			},
+			Handlers: []TaskHandler{
+				{
+					// Handler for queue task
+					TaskType: "myqueuetask:hello",
+					Queue:    "mytask",
+					Handler: func(ctx context.Context, t *asynq.Task) error {
+						time.Sleep(5 * time.Second)
+						var data map[string]interface{}
+						json.Unmarshal(t.Payload(), &data)
+						// if rand.IntN(2) == 0 {
+						// 	fmt.Printf("[myqueuetask:hello - task: %s] Payload: %v - FAILED\n", t.ResultWriter().TaskID(), data)
+						// 	return errors.New("simulate error")
+						// }
+						fmt.Printf("[myqueuetask:hello - task: %s] Payload: %v - SUCCESS\n", t.ResultWriter().TaskID(), data)
+						return nil
+					},
+				},
+				{
+					// Handler for schedule task
+					TaskType: "myscheduletask:goodbye",
+					Queue:    "mytask",
+					Handler: func(ctx context.Context, t *asynq.Task) error {
+						time.Sleep(5 * time.Second)
+						var data map[string]interface{}
+						json.Unmarshal(t.Payload(), &data)
+						// if rand.IntN(2) == 0 {
+						// 	fmt.Printf("[myscheduletask:goodbye] Payload: %v - FAILED\n", data)
+						// 	return errors.New("simulate error")
+						// }
+						fmt.Printf("[myscheduletask:goodbye] Payload: %v - SUCCESS\n", data)
+						return nil
+					},
+				},
+			},
+		}
+		if err := ValidateAsynqConfig(serverConfig); err != nil {
+			log.Fatal(err)
+		}
+
+		srv := asynq.NewServer(
+			asynq.RedisClientOpt{Addr: "127.0.0.1:6379", Password: "12345678", DB: 1},
+			serverConfig.Config,
 		)
 
-		mux := asynq.NewServeMux()
-
-		// Handler for queue task
-		mux.HandleFunc("myqueuetask:hello", func(ctx context.Context, t *asynq.Task) error {
-			time.Sleep(5 * time.Second)
-			var data map[string]interface{}
-			json.Unmarshal(t.Payload(), &data)
-			// if rand.IntN(2) == 0 {
-			// 	fmt.Printf("[myqueuetask:hello - task: %s] Payload: %v - FAILED\n", t.ResultWriter().TaskID(), data)
-			// 	return errors.New("simulate error")
-			// }
-			fmt.Printf("[myqueuetask:hello - task: %s] Payload: %v - SUCCESS\n", t.ResultWriter().TaskID(), data)
-			return nil
-		})
-
-		// Handler for schedule task
-		mux.HandleFunc("myscheduletask:goodbye", func(ctx context.Context, t *asynq.Task) error {
-			time.Sleep(5 * time.Second)
-			var data map[string]interface{}
-			json.Unmarshal(t.Payload(), &data)
-			// if rand.IntN(2) == 0 {
-			// 	fmt.Printf("[myscheduletask:goodbye] Payload: %v - FAILED\n", data)
-			// 	return errors.New("simulate error")
-			// }
-			fmt.Printf("[myscheduletask:goodbye] Payload: %v - SUCCESS\n", data)
-			return nil
-		})
+		mux := serverConfig.NewServeMux()
 
 		log.Println("Worker started...")
 		if err := srv.Run(mux); err != nil {
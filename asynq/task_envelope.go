@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// taskPropagator injects/extracts trace context of tasks with W3C Trace Context and Baggage.
+// It is used instead of the global propagator, which is a no-op unless the service sets one,
+// so the Trace Carrier is never silently empty. Producer and worker only need a Tracer provider for spans to be recorded.
+var taskPropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// TaskEnvelope is the payload of a traced task, it carries the typed payload with the Trace Carrier of producer span.
+// Trace context is injected/extracted by taskPropagator (W3C Trace Context and Baggage).
+type TaskEnvelope[T any] struct {
+	TraceCarrier propagation.MapCarrier `json:"trace_carrier"` // Trace context of producer span
+	Payload      T                      `json:"payload"`       // Typed payload of task
+}
+
+// Marshal encodes the envelope to task payload.
+func (env *TaskEnvelope[T]) Marshal() ([]byte, error) {
+	return json.Marshal(env)
+}
+
+// Unmarshal decodes the envelope from task payload.
+func (env *TaskEnvelope[T]) Unmarshal(data []byte) error {
+	return json.Unmarshal(data, env)
+}
+
+// Context returns a context continuing the trace of producer span, spans created from it are children of producer span.
+func (env *TaskEnvelope[T]) Context() context.Context {
+	return taskPropagator.Extract(context.Background(), env.TraceCarrier)
+}
+
+// NewTracedTask creates a task with payload wrapped in TaskEnvelope, which carries trace context of the span in ctx.
+//
+// Example:
+//
+//	task, err := NewTracedTask(ctx, "myqueuetask:hello", MyPayload{Count: 1}, asynq.Queue("mytask"))
+//	...
+//	mux.HandleFunc("myqueuetask:hello", func(ctx context.Context, t *asynq.Task) error {
+//	    var env TaskEnvelope[MyPayload]
+//	    if err := env.Unmarshal(t.Payload()); err != nil {
+//	        return err
+//	    }
+//	    spanCtx := env.Context()
+//	    ...
+//	})
+func NewTracedTask[T any](ctx context.Context, typename string, payload T, opts ...asynq.Option) (*asynq.Task, error) {
+	env := &TaskEnvelope[T]{
+		TraceCarrier: propagation.MapCarrier{},
+		Payload:      payload,
+	}
+	taskPropagator.Inject(ctx, env.TraceCarrier)
+
+	data, err := env.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(typename, data, opts...), nil
+}
+
+// ContextFromTask returns ctx continuing the trace of producer span if task payload is a TaskEnvelope carrying a Trace Carrier,
+// otherwise ctx is returned unchanged. Only "trace_carrier" field is decoded, so payload type doesn't need to be known.
+//
+// Example:
+//
+//	mux.HandleFunc("myqueuetask:hello", func(ctx context.Context, t *asynq.Task) error {
+//	    ctx = ContextFromTask(ctx, t)
+//	    ...
+//	})
+func ContextFromTask(ctx context.Context, task *asynq.Task) context.Context {
+	var env struct {
+		TraceCarrier propagation.MapCarrier `json:"trace_carrier"`
+	}
+	if err := json.Unmarshal(task.Payload(), &env); err != nil || len(env.TraceCarrier) == 0 {
+		return ctx
+	}
+
+	return taskPropagator.Extract(ctx, env.TraceCarrier)
+}
@@ -0,0 +1,123 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"thanhldt060802/common/pubsub"
+	"thanhldt060802/common/queuedisk"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// emptyPollInterval is interval of checking new events when queue is empty, events enqueued by Enqueue() wake publisher immediately.
+	emptyPollInterval = time.Second
+
+	retryBaseDelay = 500 * time.Millisecond
+	retryMaxDelay  = 30 * time.Second
+)
+
+// Outbox delivers events to Redis channel at least once, events are stored in Queue Disk first
+// and only removed after Redis confirms publishing, so they survive Redis outages and restarts.
+type Outbox[T any] struct {
+	queue     queuedisk.IQueueDisk[T]
+	publisher pubsub.IRedisPub[T]
+	channel   string
+
+	notify chan struct{} // Signaled by Enqueue() to wake publisher
+}
+
+// NewOutbox creates an Outbox publishing events of queue to channel, queue should not be dequeued by anyone else.
+//
+// Example:
+//
+//	queue := queuedisk.NewQueueDisk[string]("outbox_storage")
+//	ob := outbox.NewOutbox(queue, pubsub.NewRedisPub[string](redisClient), "events")
+//	go ob.Run(ctx)
+//	err := ob.Enqueue("user-created")
+func NewOutbox[T any](queue queuedisk.IQueueDisk[T], publisher pubsub.IRedisPub[T], channel string) *Outbox[T] {
+	return &Outbox[T]{
+		queue:     queue,
+		publisher: publisher,
+		channel:   channel,
+		notify:    make(chan struct{}, 1),
+	}
+}
+
+// Enqueue stores event in Queue Disk, event is published later by Run().
+// Event is not lost once Enqueue() returns nil.
+func (ob *Outbox[T]) Enqueue(event T) error {
+	if err := ob.queue.Enqueue(event); err != nil {
+		return err
+	}
+
+	select {
+	case ob.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run publishes stored events in order until ctx is done or queue is closed.
+// Failed publish is retried with capped and jittered exponential backoff, later events wait for it to keep ordering.
+// Returns nil when ctx is done, ErrQueueClosed if queue is closed.
+func (ob *Outbox[T]) Run(ctx context.Context) error {
+	failures := 0
+	for ctx.Err() == nil {
+		err := ob.queue.DequeueFunc(func(event T) error {
+			return ob.publisher.Publish(ctx, ob.channel, event)
+		})
+
+		var wait <-chan time.Time
+		switch {
+		case err == nil:
+			{
+				failures = 0
+				continue
+			}
+		case errors.Is(err, queuedisk.ErrQueueEmpty):
+			{
+				wait = time.After(emptyPollInterval)
+			}
+		case errors.Is(err, queuedisk.ErrQueueClosed):
+			{
+				return err
+			}
+		default:
+			{
+				failures++
+				delay := retryDelay(failures)
+				log.Warnf("Publish event of outbox to %v failed: %v, retrying attempt %v in %v", ob.channel, err.Error(), failures, delay)
+				wait = time.After(delay)
+			}
+		}
+
+		// New event doesn't cut backoff of a failing event short
+		notify := ob.notify
+		if failures > 0 {
+			notify = nil
+		}
+
+		select {
+		case <-ctx.Done():
+			{
+				return nil
+			}
+		case <-notify:
+		case <-wait:
+		}
+	}
+
+	return nil
+}
+
+// retryDelay returns exponential backoff delay after the failure, capped by retryMaxDelay and jittered.
+func retryDelay(failures int) time.Duration {
+	delay := retryMaxDelay
+	if failures < 16 {
+		delay = min(retryBaseDelay<<(failures-1), retryMaxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"thanhldt060802/model"
 	"time"
 
@@ -26,6 +28,13 @@ type BatchQueueDisk[T any] struct {
 	currentBatchEnqueueSize int
 
 	batchDequeue []T
+
+	metrics *batchQueueMetrics // Metrics hook (nil if disabled)
+
+	stopGC    chan struct{} // Closed by Close() to stop GC goroutine
+	gcDone    chan struct{} // Closed when GC goroutine returns
+	closed    atomic.Bool   // Set by Close(), operations after it return ErrQueueClosed
+	closeOnce sync.Once
 }
 
 type IBatchQueueDisk[T any] interface {
@@ -34,7 +43,12 @@ type IBatchQueueDisk[T any] interface {
 	Close() error
 }
 
-func NewBatchQueueDisk[T any](path string, batchSize int) IBatchQueueDisk[T] {
+func NewBatchQueueDisk[T any](path string, batchSize int, batchQueueOpts ...BatchQueueOption) IBatchQueueDisk[T] {
+	options := &batchQueueOptions{}
+	for _, opt := range batchQueueOpts {
+		opt(options)
+	}
+
 	opts := badger.DefaultOptions(path)
 	// opts.WithSyncWrites(true)
 	opts.Logger = nil
@@ -54,8 +68,16 @@ func NewBatchQueueDisk[T any](path string, batchSize int) IBatchQueueDisk[T] {
 		currentBatchEnqueueSize: 0,
 
 		batchDequeue: make([]T, batchSize),
+
+		metrics: options.metrics,
+
+		stopGC: make(chan struct{}),
+		gcDone: make(chan struct{}),
 	}
-	go bqd.GarbageCollection()
+	go func() {
+		defer close(bqd.gcDone)
+		bqd.GarbageCollection()
+	}()
 
 	return bqd
 }
@@ -68,20 +90,44 @@ func (bqd *BatchQueueDisk[T]) GarbageCollection() {
 	ticker := time.NewTicker(10 * time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := bqd.db.RunValueLogGC(0.5); err != nil && err != badger.ErrNoRewrite {
-			log.Printf("GC error: %v", err)
+	for {
+		select {
+		case <-bqd.stopGC:
+			{
+				return
+			}
+		case <-ticker.C:
+			{
+				if err := bqd.db.RunValueLogGC(0.5); err != nil && err != badger.ErrNoRewrite {
+					log.Printf("GC error: %v", err)
+				}
+			}
 		}
 	}
 }
 
+// Close stops GC goroutine, then closes Badger, data in the unflushed enqueue batch is not persisted.
+// It is idempotent, calls after the first one do nothing and return nil.
 func (bqd *BatchQueueDisk[T]) Close() error {
-	return bqd.db.Close()
+	var err error
+	bqd.closeOnce.Do(func() {
+		bqd.closed.Store(true)
+		close(bqd.stopGC)
+		<-bqd.gcDone
+
+		err = bqd.db.Close()
+	})
+	return err
 }
 
 func (bqd *BatchQueueDisk[T]) Enqueue(data T) error {
+	if bqd.closed.Load() {
+		return ErrQueueClosed
+	}
+
 	bqd.batchEnqueue[bqd.currentBatchEnqueueSize] = data
 	bqd.currentBatchEnqueueSize++
+	bqd.metrics.recordEnqueue()
 
 	if bqd.currentBatchEnqueueSize >= bqd.batchSize {
 		return bqd.db.Update(func(txn *badger.Txn) error {
@@ -110,6 +156,10 @@ func (bqd *BatchQueueDisk[T]) Enqueue(data T) error {
 }
 
 func (bqd *BatchQueueDisk[T]) Dequeue() ([]T, error) {
+	if bqd.closed.Load() {
+		return nil, ErrQueueClosed
+	}
+
 	var keysToDelete [][]byte
 	var dataDeqs []T
 
@@ -177,6 +227,9 @@ func (bqd *BatchQueueDisk[T]) Dequeue() ([]T, error) {
 
 		return nil
 	})
+	if err == nil {
+		bqd.metrics.recordDequeue(len(dataDeqs))
+	}
 
 	return dataDeqs, err
 }
@@ -0,0 +1,68 @@
+package queuedisk
+
+// IMetricRecorder records metrics of Batch Queue Disk, so queue doesn't depend on otel package.
+// An adapter over otel Observer is enough to wire it:
+//
+//	type otelMetricRecorder struct{ observer otel.IObserver }
+//
+//	func (r *otelMetricRecorder) RecordCounter(name string, value int64) {
+//	    r.observer.RecordCounter(otel.MetricName(name), value, nil)
+//	}
+//
+//	func (r *otelMetricRecorder) RecordHistogram(name string, value float64) {
+//	    r.observer.RecordHistogram(otel.MetricName(name), value, nil)
+//	}
+type IMetricRecorder interface {
+	RecordCounter(name string, value int64)
+	RecordHistogram(name string, value float64)
+}
+
+// BatchQueueOption customizes Batch Queue Disk (used by NewBatchQueueDisk()).
+type BatchQueueOption func(opts *batchQueueOptions)
+
+type batchQueueOptions struct {
+	metrics *batchQueueMetrics // Metrics hook (nil if disabled)
+}
+
+type batchQueueMetrics struct {
+	recorder           IMetricRecorder
+	enqueueCounter     string // Counter increased by number of enqueued data
+	dequeueCounter     string // Counter increased by number of dequeued data
+	batchSizeHistogram string // Histogram of batch sizes returned by Dequeue()
+}
+
+// WithMetrics records enqueue/dequeue throughput of Batch Queue Disk with recorder.
+// Metrics must be registered on recorder side (e.g. in MeterConfig.MetricDefs of otel Observer).
+//
+// Example:
+//
+//	queue := queuedisk.NewBatchQueueDisk[string]("disk_storage", 33,
+//	    queuedisk.WithMetrics(&otelMetricRecorder{observer}, "disk_queue_enqueued", "disk_queue_dequeued", "disk_queue_batch_size"),
+//	)
+func WithMetrics(recorder IMetricRecorder, enqueueCounter string, dequeueCounter string, batchSizeHistogram string) BatchQueueOption {
+	return func(opts *batchQueueOptions) {
+		opts.metrics = &batchQueueMetrics{
+			recorder:           recorder,
+			enqueueCounter:     enqueueCounter,
+			dequeueCounter:     dequeueCounter,
+			batchSizeHistogram: batchSizeHistogram,
+		}
+	}
+}
+
+// recordEnqueue records one enqueued data, it does nothing if metrics is disabled.
+func (metrics *batchQueueMetrics) recordEnqueue() {
+	if metrics == nil {
+		return
+	}
+	metrics.recorder.RecordCounter(metrics.enqueueCounter, 1)
+}
+
+// recordDequeue records a dequeued batch, it does nothing if metrics is disabled.
+func (metrics *batchQueueMetrics) recordDequeue(batchSize int) {
+	if metrics == nil {
+		return
+	}
+	metrics.recorder.RecordCounter(metrics.dequeueCounter, int64(batchSize))
+	metrics.recorder.RecordHistogram(metrics.batchSizeHistogram, float64(batchSize))
+}
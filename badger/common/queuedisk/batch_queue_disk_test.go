@@ -0,0 +1,27 @@
+package queuedisk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchQueueDiskCloseIsIdempotent(t *testing.T) {
+	bqd := NewBatchQueueDisk[string](t.TempDir(), 2)
+
+	if err := bqd.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := bqd.Close(); err != nil {
+		t.Errorf("second Close = %v, expected nil", err)
+	}
+
+	// Second enqueue fills the batch, which would write to closed Badger
+	for i := 0; i < 2; i++ {
+		if err := bqd.Enqueue("data"); !errors.Is(err, ErrQueueClosed) {
+			t.Errorf("Enqueue after Close = %v, expected ErrQueueClosed", err)
+		}
+	}
+	if _, err := bqd.Dequeue(); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Dequeue after Close = %v, expected ErrQueueClosed", err)
+	}
+}
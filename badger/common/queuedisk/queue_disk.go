@@ -1,10 +1,17 @@
 package queuedisk
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"thanhldt060802/model"
 	"time"
 
@@ -15,54 +22,227 @@ import (
 var QueueDiskInstance1 IQueueDisk[string]
 var QueueDiskInstance2 IQueueDisk[*model.DataStruct]
 
+var ErrQueueEmpty = errors.New("queue empty")
+var ErrQueueClosed = errors.New("queue closed")
+
+// dequeuePollInterval is interval of checking new data when DequeueContext() waits on empty queue.
+const dequeuePollInterval = 100 * time.Millisecond
+
+// drainBatchSize is number of data read per transaction by DrainAndClose().
+const drainBatchSize = 100
+
+// defaultGCInterval is default interval of running value log GC in background.
+const defaultGCInterval = 10 * time.Minute
+
+// DefaultPriority is priority of data enqueued without priority (e.g. by Enqueue()).
+const DefaultPriority uint8 = 128
+
+// gcDiscardRatio is minimum ratio of discardable data for a value log file to be rewritten by GC.
+const gcDiscardRatio = 0.5
+
 type QueueDisk[T any] struct {
 	db      *badger.DB
-	counter int64
+	counter atomic.Int64 // Incremented atomically, keys are created by concurrent enqueues
+
+	gcInterval time.Duration
+	stopGC     chan struct{} // Closed by Close() to stop GC goroutine
+	gcDone     chan struct{} // Closed when GC goroutine returns
+
+	closed    atomic.Bool // Set by Close(), operations after it return ErrQueueClosed
+	closeOnce sync.Once
 }
 
 type IQueueDisk[T any] interface {
 	Enqueue(data T) error
+	EnqueueContext(ctx context.Context, data T) error
+	EnqueueWithTTL(data T, ttl time.Duration) error
+	EnqueuePriority(data T, priority uint8) error
+	RequeueAfter(data T, delay time.Duration) error
 	Dequeue() (T, error)
+	DequeueContext(ctx context.Context) (T, error)
+	DequeueN(n int) ([]T, error)
+	DequeueFunc(handler func(data T) error) error
+	Len() (int, error)
+	Stats() QueueStats
 	Close() error
+	DrainAndClose(handler func(data T) error) error
 }
 
-func NewQueueDisk[T any](path string) IQueueDisk[T] {
-	opts := badger.DefaultOptions(path)
-	// opts.WithSyncWrites(true)  // No effect on Window
-	opts.Logger = nil
+// QueueStats is a snapshot of Queue Disk for monitoring.
+type QueueStats struct {
+	Pending       int           `json:"pending"`         // Number of pending data (expired data is not counted)
+	LsmSize       int64         `json:"lsm_size"`        // Approximate size of LSM tree files (bytes)
+	VlogSize      int64         `json:"vlog_size"`       // Approximate size of value log files (bytes)
+	OldestItemAge time.Duration `json:"oldest_item_age"` // Age of the oldest pending data (0 if queue is empty)
+}
 
-	db, err := badger.Open(opts)
+func NewQueueDisk[T any](path string) IQueueDisk[T] {
+	qd, err := NewQueueDiskWithOptions[T](path)
 	if err != nil {
 		log.Fatal(err)
 	}
+	return qd
+}
+
+// NewQueueDiskWithOptions is like NewQueueDisk() but customizes Badger options with opts,
+// failing to open Badger (e.g. directory is locked by another process) is returned instead of exiting.
+//
+// Example:
+//
+//	queue, err := queuedisk.NewQueueDiskWithOptions[string]("disk_storage", queuedisk.WithReadOnly())
+//	if err != nil {
+//	    ...
+//	}
+//	stats := queue.Stats()
+//
+//	queue, err := queuedisk.NewQueueDiskWithOptions[string]("disk_storage", queuedisk.WithGCInterval(time.Minute))
+func NewQueueDiskWithOptions[T any](path string, opts ...QueueOption) (IQueueDisk[T], error) {
+	queueOpts := queueOptions{
+		badgerOpts: badger.DefaultOptions(path),
+		gcInterval: defaultGCInterval,
+	}
+	// queueOpts.badgerOpts.WithSyncWrites(true)  // No effect on Window
+	queueOpts.badgerOpts.Logger = nil
+	for _, opt := range opts {
+		opt(&queueOpts)
+	}
+
+	db, err := badger.Open(queueOpts.badgerOpts)
+	if err != nil {
+		return nil, err
+	}
 
 	qd := &QueueDisk[T]{
-		db:      db,
-		counter: 0,
+		db:         db,
+		gcInterval: queueOpts.gcInterval,
+		stopGC:     make(chan struct{}),
+		gcDone:     make(chan struct{}),
+	}
+	// GC rewrites value log, which is not allowed in read-only mode
+	if queueOpts.badgerOpts.ReadOnly {
+		close(qd.gcDone)
+	} else {
+		go qd.garbageCollection()
 	}
-	go qd.garbageCollection()
 
-	return qd
+	return qd, nil
 }
 
+// garbageCollection runs value log GC every gcInterval until Close() is called.
 func (qd *QueueDisk[T]) garbageCollection() {
-	if err := qd.db.RunValueLogGC(0.5); err != nil && err != badger.ErrNoRewrite {
+	defer close(qd.gcDone)
+
+	if err := qd.runGC(); err != nil {
 		log.Errorf("GC error: %v", err)
 	}
 
-	ticker := time.NewTicker(10 * time.Minute)
+	ticker := time.NewTicker(qd.gcInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := qd.db.RunValueLogGC(0.5); err != nil && err != badger.ErrNoRewrite {
-			log.Errorf("GC error: %v", err)
+	for {
+		select {
+		case <-qd.stopGC:
+			{
+				return
+			}
+		case <-ticker.C:
+			{
+				if err := qd.runGC(); err != nil {
+					log.Errorf("GC error: %v", err)
+				}
+			}
 		}
 	}
 }
 
+// runGC rewrites value log files until there is nothing left to rewrite, then logs reclaimed space.
+// GC rejected by Badger (already running or closing) is not an error.
+func (qd *QueueDisk[T]) runGC() error {
+	_, vlogSizeBefore := qd.db.Size()
+
+	rewrites := 0
+	defer func() {
+		if rewrites > 0 {
+			_, vlogSizeAfter := qd.db.Size()
+			log.Infof("GC rewrote %v value log file(s), reclaimed %v bytes", rewrites, vlogSizeBefore-vlogSizeAfter)
+		}
+	}()
+
+	for {
+		// Stop early if Close() is called during GC
+		select {
+		case <-qd.stopGC:
+			{
+				return nil
+			}
+		default:
+		}
+
+		if err := qd.db.RunValueLogGC(gcDiscardRatio); err != nil {
+			if errors.Is(err, badger.ErrNoRewrite) || errors.Is(err, badger.ErrRejected) {
+				return nil
+			}
+			return err
+		}
+		rewrites++
+	}
+}
+
 func (qd *QueueDisk[T]) Enqueue(data T) error {
-	key := []byte(fmt.Sprintf("%020d", qd.counter))
-	qd.counter++
+	return qd.enqueue(context.Background(), data, 0, DefaultPriority)
+}
+
+// EnqueueContext is like Enqueue() but aborts and returns ctx.Err() if ctx is done before the write commits.
+func (qd *QueueDisk[T]) EnqueueContext(ctx context.Context, data T) error {
+	return qd.enqueue(ctx, data, 0, DefaultPriority)
+}
+
+// EnqueueWithTTL stores data with Badger entry TTL, expired data is never returned by Dequeue().
+// A ttl <= 0 means data never expires.
+func (qd *QueueDisk[T]) EnqueueWithTTL(data T, ttl time.Duration) error {
+	return qd.enqueue(context.Background(), data, ttl, DefaultPriority)
+}
+
+// EnqueuePriority stores data with priority, data with higher priority is dequeued first, data with the same priority is dequeued in FIFO order.
+// Enqueue() uses DefaultPriority.
+//
+// Example:
+//
+//	err := queue.EnqueuePriority(urgentNotification, 255)
+func (qd *QueueDisk[T]) EnqueuePriority(data T, priority uint8) error {
+	return qd.enqueue(context.Background(), data, 0, priority)
+}
+
+// RequeueAfter stores data which is not dequeued until delay has elapsed, e.g. to retry data failed to be processed.
+// Data whose delay has elapsed is dequeued before other data, in order of the time it became eligible.
+//
+// Example:
+//
+//	data, err := queue.Dequeue()
+//	if err := process(data); err != nil {
+//	    queue.RequeueAfter(data, 30*time.Second)
+//	}
+func (qd *QueueDisk[T]) RequeueAfter(data T, delay time.Duration) error {
+	if qd.closed.Load() {
+		return ErrQueueClosed
+	}
+
+	return qd.put(context.Background(), qd.newDelayedKey(time.Now().Add(max(delay, 0))), data, 0)
+}
+
+func (qd *QueueDisk[T]) enqueue(ctx context.Context, data T, ttl time.Duration, priority uint8) error {
+	if qd.closed.Load() {
+		return ErrQueueClosed
+	}
+
+	return qd.put(ctx, qd.newKey(priority), data, ttl)
+}
+
+// put stores data under key.
+func (qd *QueueDisk[T]) put(ctx context.Context, key []byte, data T, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	payload, err := json.Marshal(data)
 	if err != nil {
@@ -70,66 +250,521 @@ func (qd *QueueDisk[T]) Enqueue(data T) error {
 		return err
 	}
 
+	entry := badger.NewEntry(key, payload)
+	if ttl > 0 {
+		entry = entry.WithTTL(ttl)
+	}
+
 	return qd.db.Update(func(txn *badger.Txn) error {
-		return txn.Set(key, payload)
+		if err := txn.SetEntry(entry); err != nil {
+			return err
+		}
+
+		// Returning error discards the transaction
+		return ctx.Err()
 	})
 }
 
 func (qd *QueueDisk[T]) Dequeue() (T, error) {
+	return qd.dequeue(context.Background())
+}
+
+// DequeueContext blocks until data is available, then dequeues it.
+// It returns ctx.Err() if ctx is done before data is dequeued.
+func (qd *QueueDisk[T]) DequeueContext(ctx context.Context) (T, error) {
+	for {
+		data, err := qd.dequeue(ctx)
+		if !errors.Is(err, ErrQueueEmpty) {
+			return data, err
+		}
+
+		select {
+		case <-ctx.Done():
+			{
+				var zero T
+				return zero, ctx.Err()
+			}
+		case <-time.After(dequeuePollInterval):
+		}
+	}
+}
+
+func (qd *QueueDisk[T]) dequeue(ctx context.Context) (T, error) {
 	var keyToDelete []byte
 	var data T
 
+	if qd.closed.Load() {
+		return data, ErrQueueClosed
+	}
+	if err := ctx.Err(); err != nil {
+		return data, err
+	}
+
 	err := qd.db.Update(func(txn *badger.Txn) error {
 		it := txn.NewIterator(badger.DefaultIteratorOptions)
 		defer it.Close()
 
 		for it.Rewind(); it.Valid(); it.Next() {
+			if !skipDelayed(it) {
+				break
+			}
 			item := it.Item()
 			k := item.KeyCopy(nil)
+
+			// Drop expired data, Badger normally hides it but TTL may elapse during iteration
+			if isExpired(item) {
+				if err := txn.Delete(k); err != nil {
+					return err
+				}
+				continue
+			}
+
 			v, err := item.ValueCopy(nil)
 			if err != nil {
 				return err
 			}
 
-			var value T
-			t := reflect.TypeOf(value)
+			value, err := decode[T](v)
+			if err != nil {
+				log.Errorf("Unmarshal %v failed: %v", v, err.Error())
+				continue
+			}
+			data = value
+			keyToDelete = k
+
+			break
+		}
+
+		if keyToDelete == nil {
+			return ErrQueueEmpty
+		}
+
+		if err := txn.Delete(keyToDelete); err != nil {
+			return err
+		}
+
+		// Returning error discards the transaction, data stays in queue
+		return ctx.Err()
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return data, nil
+}
 
-			var instance any
-			if t.Kind() == reflect.Ptr {
-				// T is pointer to struct: create *Struct
-				instance = reflect.New(t.Elem()).Interface()
-			} else {
-				// T is value: create pointer to value (e.g., *int, *string)
-				instance = reflect.New(t).Interface()
+// DequeueFunc passes the first data to handler and deletes it only if handler succeeds,
+// so data failed by handler stays first in queue and is passed again on next call (at-least-once).
+// Returns ErrQueueEmpty if there is no data, otherwise handler error.
+// It is meant for a single consumer, concurrent calls may pass the same data to their handlers.
+//
+// Example:
+//
+//	err := queue.DequeueFunc(func(data string) error {
+//	    return redisPub.Publish(ctx, "jobs", data)
+//	})
+func (qd *QueueDisk[T]) DequeueFunc(handler func(data T) error) error {
+	if qd.closed.Load() {
+		return ErrQueueClosed
+	}
+
+	var key []byte
+	var data T
+	if err := qd.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if !skipDelayed(it) {
+				break
+			}
+			item := it.Item()
+			if isExpired(item) {
+				continue
 			}
 
-			if err := json.Unmarshal([]byte(v), instance); err != nil {
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			value, err := decode[T](v)
+			if err != nil {
 				log.Errorf("Unmarshal %v failed: %v", v, err.Error())
 				continue
 			}
+			data = value
+			key = item.KeyCopy(nil)
 
-			if t.Kind() == reflect.Ptr {
-				// T is pointer already
-				data = instance.(T)
-			} else {
-				// T is value, dereference pointer
-				data = reflect.ValueOf(instance).Elem().Interface().(T)
+			break
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+	if key == nil {
+		return ErrQueueEmpty
+	}
+
+	if err := handler(data); err != nil {
+		return err
+	}
+
+	return qd.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+}
+
+// DequeueN reads and removes up to n data in a single Badger transaction.
+// Returns fewer than n data (or an empty slice) when queue does not have enough data.
+func (qd *QueueDisk[T]) DequeueN(n int) ([]T, error) {
+	dataList := make([]T, 0, max(n, 0))
+	if qd.closed.Load() {
+		return dataList, ErrQueueClosed
+	}
+	if n <= 0 {
+		return dataList, nil
+	}
+
+	err := qd.db.Update(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		var keysToDelete [][]byte
+		for it.Rewind(); it.Valid() && len(dataList) < n; it.Next() {
+			if !skipDelayed(it) {
+				break
 			}
-			keyToDelete = k
+			item := it.Item()
+			k := item.KeyCopy(nil)
 
-			break
+			// Drop expired data, Badger normally hides it but TTL may elapse during iteration
+			if isExpired(item) {
+				keysToDelete = append(keysToDelete, k)
+				continue
+			}
+
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+
+			value, err := decode[T](v)
+			if err != nil {
+				log.Errorf("Unmarshal %v failed: %v", v, err.Error())
+				continue
+			}
+			dataList = append(dataList, value)
+			keysToDelete = append(keysToDelete, k)
 		}
 
-		if keyToDelete == nil {
-			return errors.New("queue empty")
+		for _, key := range keysToDelete {
+			if err := txn.Delete(key); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dataList, nil
+}
+
+// Len returns number of pending data in queue, expired data is not counted but delayed data (see RequeueAfter()) is.
+func (qd *QueueDisk[T]) Len() (int, error) {
+	count := 0
+	if qd.closed.Load() {
+		return count, ErrQueueClosed
+	}
+
+	err := qd.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			if isExpired(it.Item()) {
+				continue
+			}
+			count++
 		}
 
-		return txn.Delete(keyToDelete)
+		return nil
 	})
 
-	return data, err
+	return count, err
 }
 
+// Stats returns pending count, Badger size and oldest data age of queue, empty stats after Close().
+func (qd *QueueDisk[T]) Stats() QueueStats {
+	stats := QueueStats{}
+	if qd.closed.Load() {
+		return stats
+	}
+
+	pending, err := qd.Len()
+	if err != nil {
+		log.Errorf("Count pending data failed: %v", err.Error())
+	}
+	stats.Pending = pending
+
+	stats.LsmSize, stats.VlogSize = qd.db.Size()
+
+	if err := qd.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		// Keys are ordered by priority first, so the oldest data may be anywhere in queue
+		var oldest time.Time
+		for it.Rewind(); it.Valid(); it.Next() {
+			if isExpired(it.Item()) {
+				continue
+			}
+			if enqueuedAt, ok := parseKeyTime(it.Item().Key()); ok && (oldest.IsZero() || enqueuedAt.Before(oldest)) {
+				oldest = enqueuedAt
+			}
+		}
+		if !oldest.IsZero() {
+			stats.OldestItemAge = time.Since(oldest)
+		}
+
+		return nil
+	}); err != nil {
+		log.Errorf("Get oldest data failed: %v", err.Error())
+	}
+
+	return stats
+}
+
+// DiagnosticsSnapshot returns stats (see Stats()) of Queue Disk instances by name, instances not initialized are omitted.
+//
+// Example:
+//
+//	r.GET("/internal/stats", func(c *gin.Context) {
+//	    c.JSON(http.StatusOK, queuedisk.DiagnosticsSnapshot())
+//	})
+func DiagnosticsSnapshot() map[string]QueueStats {
+	snapshot := make(map[string]QueueStats)
+	if QueueDiskInstance1 != nil {
+		snapshot["queue_disk_instance_1"] = QueueDiskInstance1.Stats()
+	}
+	if QueueDiskInstance2 != nil {
+		snapshot["queue_disk_instance_2"] = QueueDiskInstance2.Stats()
+	}
+	return snapshot
+}
+
+// Close stops GC goroutine, then closes Badger.
+// It is idempotent, calls after the first one do nothing and return nil.
 func (qd *QueueDisk[T]) Close() error {
-	return qd.db.Close()
+	var err error
+	qd.closeOnce.Do(func() {
+		qd.closed.Store(true)
+		close(qd.stopGC)
+		<-qd.gcDone
+
+		err = qd.db.Close()
+	})
+	return err
+}
+
+// DrainAndClose passes every remaining data to handler in queue order, then closes Badger.
+// Data is deleted only if handler succeeds, data failed by handler (or failed to decode) stays in queue for a future run.
+// Returns all handler errors joined with Close() error.
+//
+// Example:
+//
+//	err := queue.DrainAndClose(func(data string) error {
+//	    return redisPub.Publish(ctx, "jobs", data)
+//	})
+func (qd *QueueDisk[T]) DrainAndClose(handler func(data T) error) error {
+	if qd.closed.Load() {
+		return ErrQueueClosed
+	}
+	handlerErrs := make([]error, 0)
+
+	var lastKey []byte
+	for {
+		keys, values, err := qd.readAfter(lastKey, drainBatchSize)
+		if err != nil {
+			return errors.Join(append(handlerErrs, err, qd.Close())...)
+		}
+		if len(keys) == 0 {
+			break
+		}
+		lastKey = keys[len(keys)-1]
+
+		for i, key := range keys {
+			value, err := decode[T](values[i])
+			if err != nil {
+				log.Errorf("Unmarshal %v failed: %v", values[i], err.Error())
+				continue
+			}
+
+			if err := handler(value); err != nil {
+				handlerErrs = append(handlerErrs, err)
+				continue
+			}
+
+			if err := qd.db.Update(func(txn *badger.Txn) error {
+				return txn.Delete(key)
+			}); err != nil {
+				handlerErrs = append(handlerErrs, err)
+			}
+		}
+	}
+
+	return errors.Join(append(handlerErrs, qd.Close())...)
+}
+
+// readAfter returns up to n pending keys and values after lastKey (from the beginning if lastKey is nil), expired data is skipped.
+func (qd *QueueDisk[T]) readAfter(lastKey []byte, n int) ([][]byte, [][]byte, error) {
+	keys := make([][]byte, 0, n)
+	values := make([][]byte, 0, n)
+
+	err := qd.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		if lastKey == nil {
+			it.Rewind()
+		} else {
+			it.Seek(lastKey)
+			if it.Valid() && bytes.Equal(it.Item().Key(), lastKey) {
+				it.Next()
+			}
+		}
+
+		for ; it.Valid() && len(keys) < n; it.Next() {
+			item := it.Item()
+			if isExpired(item) {
+				continue
+			}
+
+			v, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil))
+			values = append(values, v)
+		}
+
+		return nil
+	})
+
+	return keys, values, err
+}
+
+// decode unmarshals payload to T, T can be a value or a pointer.
+func decode[T any](payload []byte) (T, error) {
+	var value T
+	t := reflect.TypeOf(value)
+
+	var instance any
+	if t.Kind() == reflect.Ptr {
+		// T is pointer to struct: create *Struct
+		instance = reflect.New(t.Elem()).Interface()
+	} else {
+		// T is value: create pointer to value (e.g., *int, *string)
+		instance = reflect.New(t).Interface()
+	}
+
+	if err := json.Unmarshal(payload, instance); err != nil {
+		return value, err
+	}
+
+	if t.Kind() == reflect.Ptr {
+		// T is pointer already
+		return instance.(T), nil
+	}
+	// T is value, dereference pointer
+	return reflect.ValueOf(instance).Elem().Interface().(T), nil
+}
+
+func isExpired(item *badger.Item) bool {
+	expiresAt := item.ExpiresAt()
+	return expiresAt > 0 && expiresAt <= uint64(time.Now().Unix())
+}
+
+// skipDelayed moves iterator past delayed data whose delay has not elapsed yet, it does nothing if iterator is at another key.
+// Delayed keys are ordered by not-before time, so all delayed keys after a not-ready one are not ready either.
+// Returns whether iterator is still valid.
+func skipDelayed(it *badger.Iterator) bool {
+	notBefore, ok := parseKeyNotBefore(it.Item().Key())
+	if !ok || !notBefore.After(time.Now()) {
+		return true
+	}
+
+	it.Seek([]byte(delayedKeyPrefix + "~"))
+	return it.Valid()
+}
+
+// newKey returns key ordered by priority (descending), then by enqueue time, counter keeps keys unique in the same nanosecond.
+// Priority part is inverted ("P{255-priority}"), so Badger iterator yields higher priority first.
+// Keys of data enqueued before priority support have no priority part, they sort before all prioritized (and delayed) keys.
+func (qd *QueueDisk[T]) newKey(priority uint8) []byte {
+	key := []byte(fmt.Sprintf("%s%03d-%020d-%020d", priorityKeyPrefix, math.MaxUint8-priority, time.Now().UnixNano(), qd.counter.Add(1)))
+	return key
+}
+
+// newDelayedKey returns key ordered by not-before time, it sorts before all prioritized keys.
+func (qd *QueueDisk[T]) newDelayedKey(notBefore time.Time) []byte {
+	key := []byte(fmt.Sprintf("%s%020d-%020d", delayedKeyPrefix, notBefore.UnixNano(), qd.counter.Add(1)))
+	return key
+}
+
+// Key prefixes, delayed keys sort before prioritized keys ("D" < "P").
+const (
+	// priorityKeyPrefix marks key having priority part.
+	priorityKeyPrefix = "P"
+	// delayedKeyPrefix marks key having not-before time instead of enqueue time.
+	delayedKeyPrefix = "D"
+)
+
+// parseKeyNotBefore returns not-before time of delayed key, other keys are not parsed.
+func parseKeyNotBefore(key []byte) (time.Time, bool) {
+	rawKey, ok := strings.CutPrefix(string(key), delayedKeyPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return parseTimePart(rawKey)
+}
+
+// parseKeyTime returns enqueue time of key, keys without time part (or delayed keys) are not parsed.
+func parseKeyTime(key []byte) (time.Time, bool) {
+	rawKey := string(key)
+	if strings.HasPrefix(rawKey, delayedKeyPrefix) {
+		return time.Time{}, false
+	}
+	if strings.HasPrefix(rawKey, priorityKeyPrefix) {
+		_, rawKey, _ = strings.Cut(rawKey, "-")
+	}
+
+	return parseTimePart(rawKey)
+}
+
+// parseTimePart parses Unix nanoseconds before the first "-" of key.
+func parseTimePart(rawKey string) (time.Time, bool) {
+	rawTime, _, ok := strings.Cut(rawKey, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	unixNano, err := strconv.ParseInt(rawTime, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, unixNano), true
 }
@@ -0,0 +1,48 @@
+package queuedisk
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// queueOptions holds settings of Queue Disk customized by QueueOption.
+type queueOptions struct {
+	badgerOpts badger.Options
+	gcInterval time.Duration
+}
+
+// QueueOption customizes Queue Disk (used by NewQueueDiskWithOptions()).
+type QueueOption func(opts *queueOptions)
+
+// WithReadOnly opens Queue Disk in read-only mode, e.g. for inspecting a queue left by a stopped process.
+// Badger locks the directory exclusively while a writer has it open, so opening read-only fails while the owner process runs.
+// Only Len() and Stats() are usable, Enqueue/Dequeue return Badger read-only error and GC is not run.
+func WithReadOnly() QueueOption {
+	return func(opts *queueOptions) {
+		opts.badgerOpts = opts.badgerOpts.WithReadOnly(true)
+	}
+}
+
+// WithSyncWrites makes every write synced to disk before returning (default: false).
+func WithSyncWrites(syncWrites bool) QueueOption {
+	return func(opts *queueOptions) {
+		opts.badgerOpts = opts.badgerOpts.WithSyncWrites(syncWrites)
+	}
+}
+
+// WithValueLogFileSize sets maximum size (bytes) of each value log file.
+func WithValueLogFileSize(size int64) QueueOption {
+	return func(opts *queueOptions) {
+		opts.badgerOpts = opts.badgerOpts.WithValueLogFileSize(size)
+	}
+}
+
+// WithGCInterval sets interval of running value log GC in background (default: 10 minutes, <= 0 keeps default).
+func WithGCInterval(interval time.Duration) QueueOption {
+	return func(opts *queueOptions) {
+		if interval > 0 {
+			opts.gcInterval = interval
+		}
+	}
+}
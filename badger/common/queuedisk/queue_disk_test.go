@@ -0,0 +1,171 @@
+package queuedisk
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestQueueDisk creates Queue Disk in a temporary directory, it is closed when test ends.
+func newTestQueueDisk(t *testing.T, opts ...QueueOption) *QueueDisk[string] {
+	t.Helper()
+
+	queue, err := NewQueueDiskWithOptions[string](t.TempDir(), opts...)
+	if err != nil {
+		t.Fatalf("NewQueueDiskWithOptions: %v", err)
+	}
+	qd := queue.(*QueueDisk[string])
+	t.Cleanup(func() {
+		if err := qd.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	})
+	return qd
+}
+
+func TestEnqueueWithTTLExpires(t *testing.T) {
+	qd := newTestQueueDisk(t)
+
+	if err := qd.EnqueueWithTTL("short-lived", time.Second); err != nil {
+		t.Fatalf("EnqueueWithTTL: %v", err)
+	}
+	if err := qd.Enqueue("long-lived"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if n, err := qd.Len(); err != nil || n != 2 {
+		t.Fatalf("Len before expiry = %v, %v, expected 2", n, err)
+	}
+
+	// Badger TTL has second resolution
+	time.Sleep(2 * time.Second)
+
+	if n, err := qd.Len(); err != nil || n != 1 {
+		t.Errorf("Len after expiry = %v, %v, expected 1", n, err)
+	}
+	data, err := qd.Dequeue()
+	if err != nil || data != "long-lived" {
+		t.Errorf("Dequeue = %q, %v, expected long-lived", data, err)
+	}
+	if _, err := qd.Dequeue(); !errors.Is(err, ErrQueueEmpty) {
+		t.Errorf("Dequeue after expiry = %v, expected ErrQueueEmpty", err)
+	}
+}
+
+func TestQueueDiskCloseIsIdempotent(t *testing.T) {
+	qd := NewQueueDisk[string](t.TempDir())
+
+	if err := qd.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := qd.Close(); err != nil {
+		t.Errorf("second Close = %v, expected nil", err)
+	}
+
+	if err := qd.Enqueue("data"); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Enqueue after Close = %v, expected ErrQueueClosed", err)
+	}
+	if err := qd.RequeueAfter("data", time.Second); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("RequeueAfter after Close = %v, expected ErrQueueClosed", err)
+	}
+	if _, err := qd.Dequeue(); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Dequeue after Close = %v, expected ErrQueueClosed", err)
+	}
+	if _, err := qd.Len(); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Len after Close = %v, expected ErrQueueClosed", err)
+	}
+}
+
+func TestGarbageCollection(t *testing.T) {
+	queue, err := NewQueueDiskWithOptions[string](t.TempDir(), WithGCInterval(10*time.Millisecond), WithValueLogFileSize(1<<20))
+	if err != nil {
+		t.Fatalf("NewQueueDiskWithOptions: %v", err)
+	}
+	qd := queue.(*QueueDisk[string])
+
+	// Dequeued data leaves deleted entries behind for GC
+	payload := strings.Repeat("x", 4<<10)
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 200; i++ {
+			if err := qd.Enqueue(payload); err != nil {
+				t.Fatalf("Enqueue: %v", err)
+			}
+		}
+		dataList, err := qd.DequeueN(200)
+		if err != nil || len(dataList) != 200 {
+			t.Fatalf("DequeueN = %v items, %v, expected 200", len(dataList), err)
+		}
+		if err := qd.runGC(); err != nil {
+			t.Errorf("runGC: %v", err)
+		}
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- qd.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return, GC goroutine is not stopped")
+	}
+
+	select {
+	case <-qd.gcDone:
+	default:
+		t.Error("GC goroutine is still running after Close")
+	}
+}
+
+func TestWithReadOnly(t *testing.T) {
+	path := t.TempDir()
+	owner := NewQueueDisk[string](path)
+
+	// Directory is locked by the owner
+	if _, err := NewQueueDiskWithOptions[string](path, WithReadOnly()); err == nil {
+		t.Fatal("NewQueueDiskWithOptions read-only while owner is open = nil error, expected lock error")
+	}
+
+	if err := owner.Enqueue("data"); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := owner.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reader, err := NewQueueDiskWithOptions[string](path, WithReadOnly())
+	if err != nil {
+		t.Fatalf("NewQueueDiskWithOptions read-only after owner is closed: %v", err)
+	}
+	defer reader.Close()
+	if n, err := reader.Len(); err != nil || n != 1 {
+		t.Errorf("Len = %v, %v, expected 1", n, err)
+	}
+}
+
+func TestConcurrentEnqueueKeepsAllData(t *testing.T) {
+	qd := newTestQueueDisk(t)
+
+	const writers, perWriter = 8, 50
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				// Same priority, keys differ only by counter when enqueued in the same nanosecond
+				if err := qd.Enqueue(strconv.Itoa(w) + "-" + strconv.Itoa(i)); err != nil {
+					t.Errorf("Enqueue: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n, err := qd.Len(); err != nil || n != writers*perWriter {
+		t.Fatalf("Len = %v, %v, expected %d", n, err, writers*perWriter)
+	}
+}
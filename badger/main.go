@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"math/rand/v2"
+	"thanhldt060802/common/outbox"
+	"thanhldt060802/common/pubsub"
 	"thanhldt060802/common/queuedisk"
 	"thanhldt060802/model"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -22,6 +26,7 @@ func init() {
 		4: Example4,
 		5: Example5,
 		6: Example6,
+		7: Example7,
 	}
 }
 
@@ -238,3 +243,39 @@ func Example6() {
 
 	queuedisk.BatchQueueDiskInstance1.Close()
 }
+
+// Example for Enqueue() and Run() with Outbox.
+// Events are kept in Queue Disk while Redis is down and published once it is back.
+func Example7() {
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     "localhost:6379",
+		Password: "12345678",
+	})
+	defer redisClient.Close()
+
+	queue := queuedisk.NewQueueDisk[string]("disk_storage")
+	defer queue.Close()
+
+	ob := outbox.NewOutbox(queue, pubsub.NewRedisPub[string](redisClient), "my-channel")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := ob.Run(ctx); err != nil {
+			log.Errorf("Run outbox failed: %v", err.Error())
+		}
+	}()
+
+	for i := 1; i <= 30; i++ {
+		if err := ob.Enqueue(fmt.Sprintf("event %v", i)); err != nil {
+			log.Errorf("Enqueue failed: %v", err.Error())
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	<-done
+}
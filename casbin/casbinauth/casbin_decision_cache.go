@@ -0,0 +1,105 @@
+package casbinauth
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// decisionCache is an in-memory LRU cache with TTL for Enforce decisions, keyed on hash of Request.
+type decisionCache struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	entries    map[[sha256.Size]byte]*list.Element
+	lru        *list.List // Front is the most recently used entry
+	generation uint64     // Increased on every invalidation, so stale decisions are not stored
+}
+
+type decisionCacheEntry struct {
+	key       [sha256.Size]byte
+	allowed   bool
+	expiresAt time.Time
+}
+
+func newDecisionCache(size int, ttl time.Duration) *decisionCache {
+	return &decisionCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[[sha256.Size]byte]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// decisionCacheKey returns hash of request, ctxCondition is encoded with sorted keys so its ordering doesn't matter.
+func decisionCacheKey(request Request) ([sha256.Size]byte, error) {
+	payload, err := json.Marshal([]any{request.Subject, request.Domain, request.Object, request.Action, request.ctxCondition()})
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(payload), nil
+}
+
+// get returns cached decision of key and current generation (used by set).
+func (cache *decisionCache) get(key [sha256.Size]byte) (bool, bool, uint64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[key]
+	if !ok {
+		return false, false, cache.generation
+	}
+
+	entry := element.Value.(*decisionCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		cache.lru.Remove(element)
+		delete(cache.entries, key)
+		return false, false, cache.generation
+	}
+
+	cache.lru.MoveToFront(element)
+	return entry.allowed, true, cache.generation
+}
+
+// set stores decision of key, it is skipped if cache is invalidated after the generation.
+func (cache *decisionCache) set(key [sha256.Size]byte, allowed bool, generation uint64) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if generation != cache.generation {
+		return
+	}
+
+	if element, ok := cache.entries[key]; ok {
+		entry := element.Value.(*decisionCacheEntry)
+		entry.allowed = allowed
+		entry.expiresAt = time.Now().Add(cache.ttl)
+		cache.lru.MoveToFront(element)
+		return
+	}
+
+	cache.entries[key] = cache.lru.PushFront(&decisionCacheEntry{
+		key:       key,
+		allowed:   allowed,
+		expiresAt: time.Now().Add(cache.ttl),
+	})
+
+	// Evict the least recently used entry
+	if cache.lru.Len() > cache.size {
+		oldest := cache.lru.Back()
+		cache.lru.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*decisionCacheEntry).key)
+	}
+}
+
+// invalidate removes all cached decisions.
+func (cache *decisionCache) invalidate() {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	cache.generation++
+	cache.entries = make(map[[sha256.Size]byte]*list.Element)
+	cache.lru.Init()
+}
@@ -3,23 +3,46 @@ package casbinauth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"maps"
+	"sync/atomic"
+	"time"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"gorm.io/gorm"
 )
 
 var CasbinEnforcerInstance ICasbinEnforcer
 
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// defaultSelfReferenceValue is the condition value compared with subject by default.
+const defaultSelfReferenceValue = "owner_id"
+
+// evaluationTraceCtxKey is the context condition key carrying evaluationTrace of EnforceVerbose to inScope,
+// it can't collide with condition fields.
+const evaluationTraceCtxKey = "\x00evaluation_trace"
+
+// Retry of initial database access when creating Enforcer, a slow database on startup is waited for up to about 25s.
+const (
+	initMaxAttempts    = 6
+	initRetryBaseDelay = time.Second
+	initRetryMaxDelay  = 10 * time.Second
+)
+
 type ICasbinEnforcer interface {
 	GetPoliciesOfGroup(ctx context.Context, groupId string) (*[]Policy, error)
 	GetPoliciesOfDomain(ctx context.Context, domainId string) (*[]Policy, error)
 	AddPoliciesToGroup(ctx context.Context, policies *[]Policy) error
 	UpdatePoliciesForGroup(ctx context.Context, groupId string, policies *[]Policy) error
 	RemovePoliciesFromGroup(ctx context.Context, groupId string) error
+	RemovePolicyFromGroup(ctx context.Context, policy Policy) error
 	RemovePoliciesFromDomain(ctx context.Context, domainId string) error
+	CountPoliciesOfDomain(ctx context.Context, domainId string) (int, int, error)
 
 	GetGroupingPoliciesOfGroup(ctx context.Context, groupId string) (*[]GroupingPolicy, error)
 	GetGroupingPoliciesOfDomain(ctx context.Context, domainId string) (*[]GroupingPolicy, error)
@@ -28,40 +51,132 @@ type ICasbinEnforcer interface {
 	RemoveGroupingPolicyFromGroup(ctx context.Context, groupId string, subjectId string) error
 	RemoveGroupingPoliciesFromGroup(ctx context.Context, groupId string) error
 	RemoveGroupingPoliciesFromDomain(ctx context.Context, domainId string) error
+	RemoveAllRolesFromUser(ctx context.Context, subject string, domain string) (int, error)
+
+	AddRoleInheritance(ctx context.Context, childRole string, parentRole string, domain string) error
+	RemoveRoleInheritance(ctx context.Context, childRole string, parentRole string, domain string) error
 
 	Enforce(ctx context.Context, request Request) (bool, error)
+	EnforceEx(ctx context.Context, request Request) (bool, *Policy, DenyReason, error)
+	EnforceVerbose(ctx context.Context, request Request) (bool, []string, error)
+	EnforceBatch(ctx context.Context, requests []Request) ([]bool, error)
+	EnforceAny(ctx context.Context, requests []Request) (bool, error)
+	EnforceAll(ctx context.Context, requests []Request) (bool, error)
+	SetAuditHook(hook AuditHook)
+	SetSelfReferenceValues(values ...string)
+	RegisterKnownFields(fields []string, mode UnknownFieldMode)
 
 	Save(ctx context.Context) error
 }
 
 type CasbinEnforcer struct {
-	enforcer *casbin.Enforcer
+	enforcer      *casbin.Enforcer
+	decisionCache *decisionCache                      // Cache of Enforce decisions (nil if disabled)
+	auditHook     atomic.Pointer[AuditHook]           // Hook fired on every Enforce decision (nil if not set)
+	selfRefs      atomic.Pointer[map[string]struct{}] // Condition values compared with subject instead of literally (default: owner_id)
+	knownFields   atomic.Pointer[knownFieldSet]       // Fields allowed in conditions (nil if not registered)
 }
 
-func NewCasbinEnforcer(configFile string, db *gorm.DB) ICasbinEnforcer {
-	adapter, err := gormadapter.NewAdapterByDBWithCustomTable(db, &CustomCasbinRule{})
-	if err != nil {
-		log.Fatalf("Failed to create Casbin adapter: %v", err.Error())
+// knownFieldSet is the set of condition fields registered by RegisterKnownFields with handling of unknown fields.
+type knownFieldSet struct {
+	fields map[string]struct{}
+	mode   UnknownFieldMode
+}
+
+// AuditHook is called with every Enforce decision, request is a copy so the hook can't mutate internal state.
+type AuditHook func(request Request, allowed bool, err error)
+
+// NewCasbinEnforcer creates Enforcer of model configFile with policies stored in db.
+// Loading policies is retried with exponential backoff, so a slow database on startup doesn't fail it.
+//
+// Example:
+//
+//	enforcer, err := casbinauth.NewCasbinEnforcer("config/hybrid_model.conf", db)
+//	if err != nil {
+//		...
+//	}
+func NewCasbinEnforcer(configFile string, db *gorm.DB) (ICasbinEnforcer, error) {
+	var adapter *gormadapter.Adapter
+	if err := retryWithBackoff("create Casbin adapter", func() error {
+		var err error
+		adapter, err = gormadapter.NewAdapterByDBWithCustomTable(db, &CustomCasbinRule{})
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create Casbin adapter: %w", err)
 	}
 
-	enforcer, err := casbin.NewEnforcer(configFile, adapter)
+	// Adapter is set after creating, so the initial LoadPolicy is retried below instead of failing in casbin.NewEnforcer
+	enforcer, err := casbin.NewEnforcer(configFile)
 	if err != nil {
-		log.Fatalf("Failed to create Enforcer: %v", err.Error())
+		return nil, fmt.Errorf("failed to create Enforcer: %w", err)
 	}
+	enforcer.SetAdapter(adapter)
 	enforcer.EnableAutoSave(false)
 
-	if err := enforcer.LoadPolicy(); err != nil {
-		log.Fatalf("Failed to load Policy for Enforcer: %v", err.Error())
+	if err := retryWithBackoff("load Policy for Enforcer", enforcer.LoadPolicy); err != nil {
+		return nil, fmt.Errorf("failed to load Policy for Enforcer: %w", err)
 	}
 
+	return newCasbinEnforcer(enforcer), nil
+}
+
+// newCasbinEnforcer wraps enforcer with default self-reference values and registers custom matcher functions.
+func newCasbinEnforcer(enforcer *casbin.Enforcer) *CasbinEnforcer {
 	casbinEnf := &CasbinEnforcer{
 		enforcer: enforcer,
 	}
+	casbinEnf.SetSelfReferenceValues(defaultSelfReferenceValue)
 	casbinEnf.enforcer.AddFunction("inScope", casbinEnf.inScope)
+	casbinEnf.enforcer.AddFunction("wildcardMatch", casbinEnf.wildcardMatch)
+
+	return casbinEnf
+}
 
+// MustNewCasbinEnforcer is like NewCasbinEnforcer but exits the process if creating Enforcer fails.
+func MustNewCasbinEnforcer(configFile string, db *gorm.DB) ICasbinEnforcer {
+	casbinEnf, err := NewCasbinEnforcer(configFile, db)
+	if err != nil {
+		log.Fatalf("Failed to create Casbin Enforcer: %v", err.Error())
+	}
 	return casbinEnf
 }
 
+// NewCasbinEnforcerWithCache is like NewCasbinEnforcer but caches Enforce decisions in an LRU cache of cacheSize entries, each entry expires after ttl.
+// Cache is invalidated on any policy mutation.
+func NewCasbinEnforcerWithCache(configFile string, db *gorm.DB, cacheSize int, ttl time.Duration) (ICasbinEnforcer, error) {
+	if cacheSize <= 0 || ttl <= 0 {
+		return nil, errors.New("invalid decision cache config: cache size and ttl must be positive")
+	}
+
+	enforcer, err := NewCasbinEnforcer(configFile, db)
+	if err != nil {
+		return nil, err
+	}
+	casbinEnf := enforcer.(*CasbinEnforcer)
+	casbinEnf.decisionCache = newDecisionCache(cacheSize, ttl)
+
+	return casbinEnf, nil
+}
+
+// retryWithBackoff calls fn until it succeeds, up to initMaxAttempts times with exponential backoff between attempts.
+// Returns error of the last attempt.
+func retryWithBackoff(operation string, fn func() error) error {
+	delay := initRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt == initMaxAttempts {
+			return err
+		}
+
+		log.Printf("[warning] Failed to %s (attempt %d/%d): %v, retrying in %v", operation, attempt, initMaxAttempts, err, delay)
+		time.Sleep(delay)
+		delay = min(delay*2, initRetryMaxDelay)
+	}
+}
+
 func (casbinEnf *CasbinEnforcer) GetPoliciesOfGroup(ctx context.Context, groupId string) (*[]Policy, error) {
 	rawPolicies, err := casbinEnf.enforcer.GetFilteredPolicy(0, groupId)
 	if err != nil {
@@ -103,7 +218,24 @@ func (casbinEnf *CasbinEnforcer) GetPoliciesOfDomain(ctx context.Context, domain
 }
 
 func (casbinEnf *CasbinEnforcer) AddPoliciesToGroup(ctx context.Context, policies *[]Policy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Validate all conditions first, so a rejected policy doesn't leave the batch half added
+	for _, policy := range *policies {
+		if err := casbinEnf.checkConditionFields(policy.Condition); err != nil {
+			return err
+		}
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	for _, policy := range *policies {
+		// Abort the batch if ctx is done, policies added before are kept
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if _, err := casbinEnf.enforcer.AddPolicy(policy.SubjectGroup, policy.Domain, policy.Object, policy.Action, policy.Condition); err != nil {
 			return err
 		}
@@ -112,6 +244,10 @@ func (casbinEnf *CasbinEnforcer) AddPoliciesToGroup(ctx context.Context, policie
 }
 
 func (casbinEnf *CasbinEnforcer) UpdatePoliciesForGroup(ctx context.Context, groupId string, policies *[]Policy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	if err := casbinEnf.RemovePoliciesFromGroup(ctx, groupId); err != nil {
 		return err
 	}
@@ -122,15 +258,74 @@ func (casbinEnf *CasbinEnforcer) UpdatePoliciesForGroup(ctx context.Context, gro
 }
 
 func (casbinEnf *CasbinEnforcer) RemovePoliciesFromGroup(ctx context.Context, groupId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.RemoveFilteredPolicy(0, groupId)
 	return err
 }
 
+// RemovePolicyFromGroup removes exactly the matching policy, returns ErrPolicyNotFound if no policy matches.
+func (casbinEnf *CasbinEnforcer) RemovePolicyFromGroup(ctx context.Context, policy Policy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
+	removed, err := casbinEnf.enforcer.RemovePolicy(policy.SubjectGroup, policy.Domain, policy.Object, policy.Action, policy.Condition)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
+
 func (casbinEnf *CasbinEnforcer) RemovePoliciesFromDomain(ctx context.Context, domainId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.RemoveFilteredPolicy(1, domainId)
 	return err
 }
 
+// CountPoliciesOfDomain returns number of policies and grouping policies of domain.
+// It counts on the loaded model directly, so policies are not copied.
+func (casbinEnf *CasbinEnforcer) CountPoliciesOfDomain(ctx context.Context, domainId string) (int, int, error) {
+	policyAssertion, ok := casbinEnf.enforcer.GetModel()["p"]["p"]
+	if !ok {
+		return 0, 0, fmt.Errorf("policy definition 'p' is not found in model")
+	}
+	groupingPolicyAssertion, ok := casbinEnf.enforcer.GetModel()["g"]["g"]
+	if !ok {
+		return 0, 0, fmt.Errorf("role definition 'g' is not found in model")
+	}
+
+	policies := 0
+	for _, rawPolicy := range policyAssertion.Policy {
+		if len(rawPolicy) > 1 && rawPolicy[1] == domainId {
+			policies++
+		}
+	}
+
+	groupingPolicies := 0
+	for _, rawGroupingPolicy := range groupingPolicyAssertion.Policy {
+		if len(rawGroupingPolicy) > 2 && rawGroupingPolicy[2] == domainId {
+			groupingPolicies++
+		}
+	}
+
+	return policies, groupingPolicies, nil
+}
+
 func (casbinEnf *CasbinEnforcer) GetGroupingPoliciesOfGroup(ctx context.Context, groupId string) (*[]GroupingPolicy, error) {
 	rawGroupingPolicies, err := casbinEnf.enforcer.GetFilteredGroupingPolicy(1, groupId)
 	if err != nil {
@@ -168,12 +363,28 @@ func (casbinEnf *CasbinEnforcer) GetGroupingPoliciesOfDomain(ctx context.Context
 }
 
 func (casbinEnf *CasbinEnforcer) AddGroupingPolicyToGroup(ctx context.Context, groupingPolicy *GroupingPolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.AddGroupingPolicy(groupingPolicy.Subject, groupingPolicy.SubjectGroup, groupingPolicy.Domain)
 	return err
 }
 
 func (casbinEnf *CasbinEnforcer) AddGroupingPoliciesToGroup(ctx context.Context, groupingPolicies *[]GroupingPolicy) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	for _, groupingPolicy := range *groupingPolicies {
+		// Abort the batch if ctx is done, policies added before are kept
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		if _, err := casbinEnf.enforcer.AddGroupingPolicy(groupingPolicy.Subject, groupingPolicy.SubjectGroup, groupingPolicy.Domain); err != nil {
 			return err
 		}
@@ -182,35 +393,437 @@ func (casbinEnf *CasbinEnforcer) AddGroupingPoliciesToGroup(ctx context.Context,
 }
 
 func (casbinEnf *CasbinEnforcer) RemoveGroupingPolicyFromGroup(ctx context.Context, groupId string, subjectId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.RemoveFilteredGroupingPolicy(0, subjectId, groupId)
 	return err
 }
 
 func (casbinEnf *CasbinEnforcer) RemoveGroupingPoliciesFromGroup(ctx context.Context, groupId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.RemoveFilteredGroupingPolicy(1, groupId)
 	return err
 }
 
 func (casbinEnf *CasbinEnforcer) RemoveGroupingPoliciesFromDomain(ctx context.Context, domainId string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
 	_, err := casbinEnf.enforcer.RemoveFilteredGroupingPolicy(2, domainId)
 	return err
 }
 
+// RemoveAllRolesFromUser removes subject from every role (grouping policy) in domain, e.g. when offboarding a user.
+// Returns number of removed grouping policies.
+func (casbinEnf *CasbinEnforcer) RemoveAllRolesFromUser(ctx context.Context, subject string, domain string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
+	rawGroupingPolicies, err := casbinEnf.enforcer.GetFilteredGroupingPolicy(0, subject, "", domain)
+	if err != nil {
+		return 0, err
+	}
+	if len(rawGroupingPolicies) == 0 {
+		return 0, nil
+	}
+
+	if _, err := casbinEnf.enforcer.RemoveFilteredGroupingPolicy(0, subject, "", domain); err != nil {
+		return 0, err
+	}
+
+	return len(rawGroupingPolicies), nil
+}
+
+// AddRoleInheritance makes childRole inherit all permissions of parentRole in domain.
+// It is stored as grouping policy (childRole, parentRole, domain), role manager resolves inheritance transitively.
+func (casbinEnf *CasbinEnforcer) AddRoleInheritance(ctx context.Context, childRole string, parentRole string, domain string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
+	if childRole == parentRole {
+		return fmt.Errorf("role '%s' can not inherit itself", childRole)
+	}
+
+	// Reject cycle, parentRole must not already inherit childRole
+	hasLink, err := casbinEnf.enforcer.GetRoleManager().HasLink(parentRole, childRole, domain)
+	if err != nil {
+		return err
+	}
+	if hasLink {
+		return fmt.Errorf("role '%s' already inherits role '%s' in domain '%s'", parentRole, childRole, domain)
+	}
+
+	_, err = casbinEnf.enforcer.AddGroupingPolicy(childRole, parentRole, domain)
+	return err
+}
+
+// RemoveRoleInheritance removes inheritance of childRole from parentRole in domain.
+func (casbinEnf *CasbinEnforcer) RemoveRoleInheritance(ctx context.Context, childRole string, parentRole string, domain string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
+	_, err := casbinEnf.enforcer.RemoveGroupingPolicy(childRole, parentRole, domain)
+	return err
+}
+
 func (casbinEnf *CasbinEnforcer) Enforce(ctx context.Context, request Request) (bool, error) {
-	return casbinEnf.enforcer.Enforce(request.Subject, request.Domain, request.Object, request.Action, request.CtxCondition)
+	allowed, err := casbinEnf.enforce(request)
+	casbinEnf.audit(request, allowed, err)
+	return allowed, err
+}
+
+func (casbinEnf *CasbinEnforcer) enforce(request Request) (bool, error) {
+	if casbinEnf.decisionCache == nil {
+		return casbinEnf.enforcer.Enforce(request.Subject, request.Domain, request.Object, request.Action, request.ctxCondition())
+	}
+
+	key, err := decisionCacheKey(request)
+	if err != nil {
+		return false, err
+	}
+	allowed, ok, generation := casbinEnf.decisionCache.get(key)
+	if ok {
+		return allowed, nil
+	}
+
+	allowed, err = casbinEnf.enforcer.Enforce(request.Subject, request.Domain, request.Object, request.Action, request.ctxCondition())
+	if err != nil {
+		return false, err
+	}
+	casbinEnf.decisionCache.set(key, allowed, generation)
+
+	return allowed, nil
+}
+
+// EnforceBatch enforces requests in order and returns decisions in the same order, every decision is audited.
+func (casbinEnf *CasbinEnforcer) EnforceBatch(ctx context.Context, requests []Request) ([]bool, error) {
+	return casbinEnf.enforceBatch(ctx, requests, nil)
+}
+
+// EnforceAny returns true if any request is allowed, it stops at the first allowed request.
+// Returns false if requests is empty.
+//
+// Example:
+//
+//	allowed, err := casbinEnforcer.EnforceAny(ctx, []casbinauth.Request{
+//	    {Subject: userId, Domain: domainId, Object: "report", Action: "read"},
+//	    {Subject: userId, Domain: domainId, Object: "report", Action: "manage"},
+//	})
+func (casbinEnf *CasbinEnforcer) EnforceAny(ctx context.Context, requests []Request) (bool, error) {
+	decisions, err := casbinEnf.enforceBatch(ctx, requests, func(allowed bool) bool {
+		return allowed
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(decisions) > 0 && decisions[len(decisions)-1], nil
+}
+
+// EnforceAll returns true only if all requests are allowed, it stops at the first denied request.
+// Returns false if requests is empty, so an empty check never grants access.
+func (casbinEnf *CasbinEnforcer) EnforceAll(ctx context.Context, requests []Request) (bool, error) {
+	decisions, err := casbinEnf.enforceBatch(ctx, requests, func(allowed bool) bool {
+		return !allowed
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return len(decisions) > 0 && decisions[len(decisions)-1], nil
+}
+
+// enforceBatch enforces requests in order until stop (if not nil) returns true for a decision, returns decisions made so far.
+func (casbinEnf *CasbinEnforcer) enforceBatch(ctx context.Context, requests []Request, stop func(allowed bool) bool) ([]bool, error) {
+	decisions := make([]bool, 0, len(requests))
+	for _, request := range requests {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		allowed, err := casbinEnf.enforce(request)
+		casbinEnf.audit(request, allowed, err)
+		if err != nil {
+			return nil, err
+		}
+
+		decisions = append(decisions, allowed)
+		if stop != nil && stop(allowed) {
+			break
+		}
+	}
+
+	return decisions, nil
+}
+
+// EnforceEx is like Enforce but also explains the decision, it bypasses decision cache.
+// If allowed, it returns the matched policy. If denied, it returns reason of denial (DENY_REASON_*).
+func (casbinEnf *CasbinEnforcer) EnforceEx(ctx context.Context, request Request) (bool, *Policy, DenyReason, error) {
+	allowed, explain, err := casbinEnf.enforcer.EnforceEx(request.Subject, request.Domain, request.Object, request.Action, request.ctxCondition())
+	casbinEnf.audit(request, allowed, err)
+	if err != nil {
+		return false, nil, DENY_REASON_NONE, err
+	}
+
+	if allowed {
+		var policy *Policy
+		if len(explain) >= 5 {
+			policy = &Policy{
+				SubjectGroup: explain[0],
+				Domain:       explain[1],
+				Object:       explain[2],
+				Action:       explain[3],
+				Condition:    explain[4],
+			}
+		}
+		return true, policy, DENY_REASON_NONE, nil
+	}
+
+	reason, err := casbinEnf.denyReason(request)
+	if err != nil {
+		return false, nil, DENY_REASON_NONE, err
+	}
+
+	return false, nil, reason, nil
+}
+
+// EnforceVerbose is like Enforce but also returns a human-readable trace of condition evaluation, it bypasses decision cache.
+// Intended for debugging while authoring policies, every evaluated policy condition is traced with its "and"/"or" branches.
+//
+// Example:
+//
+//	allowed, trace, err := casbinEnforcer.EnforceVerbose(ctx, request)
+//	for _, line := range trace {
+//	    log.Println(line)
+//	}
+//	// condition {"or":{"team_id_in":["t1","t2"],"owner_id_eq":"owner_id"}} -> true
+//	//   or -> true
+//	//     owner_id_eq: u2 equals subject u1 -> false
+//	//     team_id_in: t1 in [t1 t2] -> true
+func (casbinEnf *CasbinEnforcer) EnforceVerbose(ctx context.Context, request Request) (bool, []string, error) {
+	// Trace is threaded through context condition of this request only, so concurrent calls don't share it
+	trace := &evaluationTrace{}
+	ctxCondition := request.ctxCondition()
+	ctxCondition[evaluationTraceCtxKey] = trace
+
+	allowed, err := casbinEnf.enforcer.Enforce(request.Subject, request.Domain, request.Object, request.Action, ctxCondition)
+	casbinEnf.audit(request, allowed, err)
+	return allowed, trace.result(), err
+}
+
+// denyReason inspects policies of request domain to explain why request is denied.
+// A policy of subject matching object and action means its condition failed, otherwise a policy matching object and action means subject misses its role.
+func (casbinEnf *CasbinEnforcer) denyReason(request Request) (DenyReason, error) {
+	rawPolicies, err := casbinEnf.enforcer.GetFilteredPolicy(1, request.Domain)
+	if err != nil {
+		return DENY_REASON_NONE, err
+	}
+
+	reason := DENY_REASON_NO_MATCHING_POLICY
+	for _, rawPolicy := range rawPolicies {
+		if !wildcardMatch(request.Object, rawPolicy[2]) || !wildcardMatch(request.Action, rawPolicy[3]) {
+			continue
+		}
+
+		hasRole, err := casbinEnf.enforcer.GetRoleManager().HasLink(request.Subject, rawPolicy[0], request.Domain)
+		if err != nil {
+			return DENY_REASON_NONE, err
+		}
+		if hasRole {
+			return DENY_REASON_CONDITION_FAILED, nil
+		}
+		reason = DENY_REASON_ROLE_NOT_ASSIGNED
+	}
+
+	return reason, nil
 }
 
 func (casbinEnf *CasbinEnforcer) Save(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	defer casbinEnf.invalidateDecisionCache()
+
+	// Pass ctx through to adapter if it supports context (e.g. gorm adapter)
+	if adapter, ok := casbinEnf.enforcer.GetAdapter().(persist.ContextAdapter); ok {
+		return adapter.SavePolicyCtx(ctx, casbinEnf.enforcer.GetModel())
+	}
 	return casbinEnf.enforcer.SavePolicy()
 }
 
+// SetAuditHook sets hook fired after every Enforce decision, nil removes the hook.
+func (casbinEnf *CasbinEnforcer) SetAuditHook(hook AuditHook) {
+	if hook == nil {
+		casbinEnf.auditHook.Store(nil)
+		return
+	}
+	casbinEnf.auditHook.Store(&hook)
+}
+
+// audit fires audit hook (if set) with a copy of request, it runs after enforcer returns so it doesn't hold enforcer lock.
+func (casbinEnf *CasbinEnforcer) audit(request Request, allowed bool, err error) {
+	hook := casbinEnf.auditHook.Load()
+	if hook == nil {
+		return
+	}
+
+	requestCopy := request
+	requestCopy.CtxCondition = maps.Clone(request.CtxCondition)
+	requestCopy.CtxConditionAny = maps.Clone(request.CtxConditionAny)
+	(*hook)(requestCopy, allowed, err)
+}
+
+// SetSelfReferenceValues sets condition values meaning "the subject itself", e.g. with "assignee_id",
+// condition {"assignee_id_eq": "assignee_id"} matches if ctxCondition["assignee_id"] equals subject.
+// Default is only "owner_id", calling without values disables self-reference.
+func (casbinEnf *CasbinEnforcer) SetSelfReferenceValues(values ...string) {
+	defer casbinEnf.invalidateDecisionCache()
+
+	selfRefs := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		selfRefs[value] = struct{}{}
+	}
+	casbinEnf.selfRefs.Store(&selfRefs)
+}
+
+// RegisterKnownFields registers fields allowed in policy conditions (e.g. "team_id", "department_id"),
+// so a condition referencing an unknown field (e.g. typo "team_idd", which never matches) is caught.
+// Conditions of already loaded policies are checked once on registering, conditions of new policies on AddPoliciesToGroup.
+// With UNKNOWN_FIELD_MODE_WARN a warning is logged, with UNKNOWN_FIELD_MODE_ERROR the policy is rejected and Enforce fails.
+// Calling with empty fields disables the check.
+//
+// Example:
+//
+//	casbinEnforcer.RegisterKnownFields([]string{"owner_id", "team_id", "department_id"}, casbinauth.UNKNOWN_FIELD_MODE_ERROR)
+func (casbinEnf *CasbinEnforcer) RegisterKnownFields(fields []string, mode UnknownFieldMode) {
+	defer casbinEnf.invalidateDecisionCache()
+
+	if len(fields) == 0 {
+		casbinEnf.knownFields.Store(nil)
+		return
+	}
+
+	knownFields := &knownFieldSet{
+		fields: make(map[string]struct{}, len(fields)),
+		mode:   mode,
+	}
+	for _, field := range fields {
+		knownFields.fields[field] = struct{}{}
+	}
+	casbinEnf.knownFields.Store(knownFields)
+
+	casbinEnf.checkLoadedConditionFields()
+}
+
+// checkLoadedConditionFields checks conditions of loaded policies against known fields, each distinct condition is reported once.
+// Enforce doesn't warn about unknown fields, so this is where loaded policies are reported.
+func (casbinEnf *CasbinEnforcer) checkLoadedConditionFields() {
+	rawPolicies, err := casbinEnf.enforcer.GetPolicy()
+	if err != nil {
+		log.Printf("[warning] Failed to check conditions of loaded policies: %v", err)
+		return
+	}
+
+	checked := make(map[string]struct{})
+	for _, rawPolicy := range rawPolicies {
+		if len(rawPolicy) < 5 {
+			continue
+		}
+		rawCondition := rawPolicy[4]
+		if _, ok := checked[rawCondition]; ok {
+			continue
+		}
+		checked[rawCondition] = struct{}{}
+
+		if err := casbinEnf.checkConditionFields(rawCondition); err != nil {
+			log.Printf("[warning] Loaded policy %v is rejected by Enforce: %v", rawPolicy, err)
+		}
+	}
+}
+
+// checkConditionFields checks raw condition of a policy against known fields, it does nothing if known fields are not registered.
+// Malformed condition is left to Enforce, which reports it with error.
+func (casbinEnf *CasbinEnforcer) checkConditionFields(rawCondition string) error {
+	if rawCondition == "*" || casbinEnf.knownFields.Load() == nil {
+		return nil
+	}
+
+	var condition map[string]any
+	if err := json.Unmarshal([]byte(rawCondition), &condition); err != nil {
+		return nil
+	}
+	return casbinEnf.checkUnknownFields(rawCondition, condition)
+}
+
+// checkUnknownFields logs a warning or returns an error (by mode) if condition references fields not registered by RegisterKnownFields.
+func (casbinEnf *CasbinEnforcer) checkUnknownFields(rawCondition string, condition map[string]any) error {
+	knownFields := casbinEnf.knownFields.Load()
+	if knownFields == nil {
+		return nil
+	}
+
+	unknown := unknownConditionFields(condition, knownFields.fields)
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	if knownFields.mode == UNKNOWN_FIELD_MODE_ERROR {
+		return fmt.Errorf("condition '%s' references unknown fields %v", rawCondition, unknown)
+	}
+	log.Printf("[warning] Condition '%s' references unknown fields %v", rawCondition, unknown)
+	return nil
+}
+
+// rejectUnknownFields returns an error if condition references unknown fields in UNKNOWN_FIELD_MODE_ERROR.
+// It never logs, as it runs for every evaluated policy on Enforce.
+func (casbinEnf *CasbinEnforcer) rejectUnknownFields(rawCondition string, condition map[string]any) error {
+	knownFields := casbinEnf.knownFields.Load()
+	if knownFields == nil || knownFields.mode != UNKNOWN_FIELD_MODE_ERROR {
+		return nil
+	}
+
+	if unknown := unknownConditionFields(condition, knownFields.fields); len(unknown) > 0 {
+		return fmt.Errorf("condition '%s' references unknown fields %v", rawCondition, unknown)
+	}
+	return nil
+}
+
+// invalidateDecisionCache removes cached decisions after policy mutation, it does nothing if cache is disabled.
+func (casbinEnf *CasbinEnforcer) invalidateDecisionCache() {
+	if casbinEnf.decisionCache != nil {
+		casbinEnf.decisionCache.invalidate()
+	}
+}
+
 func (casbinEnf *CasbinEnforcer) inScope(args ...interface{}) (interface{}, error) {
 	subject, ok := args[0].(string)
 	if !ok {
 		return false, fmt.Errorf("failed to parse subject")
 	}
 
-	ctxCondition, ok := args[1].(map[string]string)
+	ctxCondition, ok := args[1].(map[string]any)
 	if !ok {
 		return false, fmt.Errorf("failed to ctxCondition subject")
 	}
@@ -227,5 +840,34 @@ func (casbinEnf *CasbinEnforcer) inScope(args ...interface{}) (interface{}, erro
 		}
 	}
 
-	return inScope(subject, ctxCondition, condition), nil
+	trace, _ := ctxCondition[evaluationTraceCtxKey].(*evaluationTrace)
+	line := trace.begin()
+
+	if err := casbinEnf.rejectUnknownFields(rawCondition, condition); err != nil {
+		trace.end(line, "condition %s -> error: %v", rawCondition, err)
+		return false, err
+	}
+
+	// Malformed condition fails Enforce with error instead of evaluating silently
+	ok, err := inScopeE(subject, ctxCondition, condition, *casbinEnf.selfRefs.Load(), trace)
+	if err != nil {
+		trace.end(line, "condition %s -> error: %v", rawCondition, err)
+		return false, fmt.Errorf("malformed condition '%s': %v", rawCondition, err)
+	}
+	trace.end(line, "condition %s -> %v", rawCondition, ok)
+	return ok, nil
+}
+
+func (casbinEnf *CasbinEnforcer) wildcardMatch(args ...interface{}) (interface{}, error) {
+	value, ok := args[0].(string)
+	if !ok {
+		return false, fmt.Errorf("failed to parse value")
+	}
+
+	pattern, ok := args[1].(string)
+	if !ok {
+		return false, fmt.Errorf("failed to parse pattern")
+	}
+
+	return wildcardMatch(value, pattern), nil
 }
@@ -0,0 +1,146 @@
+package casbinauth
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// newTestCasbinEnforcer creates Enforcer of the hybrid model without adapter, policies are kept in memory only.
+func newTestCasbinEnforcer(t *testing.T) *CasbinEnforcer {
+	t.Helper()
+
+	enforcer, err := casbin.NewEnforcer("../config/hybrid_model.conf")
+	if err != nil {
+		t.Fatalf("create Enforcer: %v", err)
+	}
+	return newCasbinEnforcer(enforcer)
+}
+
+// addTestPolicies adds policies and grouping policies to casbinEnf, failing the test on error.
+func addTestPolicies(t *testing.T, casbinEnf *CasbinEnforcer, policies []Policy, groupingPolicies []GroupingPolicy) {
+	t.Helper()
+
+	if err := casbinEnf.AddPoliciesToGroup(context.Background(), &policies); err != nil {
+		t.Fatalf("AddPoliciesToGroup: %v", err)
+	}
+	if len(groupingPolicies) > 0 {
+		if err := casbinEnf.AddGroupingPoliciesToGroup(context.Background(), &groupingPolicies); err != nil {
+			t.Fatalf("AddGroupingPoliciesToGroup: %v", err)
+		}
+	}
+}
+
+func TestAddRoleInheritanceTwoLevels(t *testing.T) {
+	ctx := context.Background()
+	casbinEnf := newTestCasbinEnforcer(t)
+
+	addTestPolicies(t, casbinEnf,
+		[]Policy{
+			{SubjectGroup: "viewer", Domain: "domain_1", Object: "document", Action: "view", Condition: "*"},
+			{SubjectGroup: "editor", Domain: "domain_1", Object: "document", Action: "edit", Condition: "*"},
+			{SubjectGroup: "admin", Domain: "domain_1", Object: "document", Action: "delete", Condition: "*"},
+		},
+		[]GroupingPolicy{
+			{Subject: "user_admin", SubjectGroup: "admin", Domain: "domain_1"},
+			{Subject: "user_editor", SubjectGroup: "editor", Domain: "domain_1"},
+		},
+	)
+
+	// admin -> editor -> viewer
+	if err := casbinEnf.AddRoleInheritance(ctx, "admin", "editor", "domain_1"); err != nil {
+		t.Fatalf("AddRoleInheritance(admin, editor): %v", err)
+	}
+	if err := casbinEnf.AddRoleInheritance(ctx, "editor", "viewer", "domain_1"); err != nil {
+		t.Fatalf("AddRoleInheritance(editor, viewer): %v", err)
+	}
+
+	tests := []struct {
+		subject string
+		domain  string
+		action  string
+		allowed bool
+	}{
+		{"user_admin", "domain_1", "delete", true},
+		{"user_admin", "domain_1", "edit", true},
+		{"user_admin", "domain_1", "view", true},
+		{"user_editor", "domain_1", "view", true},
+		{"user_editor", "domain_1", "delete", false},
+		{"user_admin", "domain_2", "view", false},
+	}
+	for _, tt := range tests {
+		allowed, err := casbinEnf.Enforce(ctx, Request{Subject: tt.subject, Domain: tt.domain, Object: "document", Action: tt.action})
+		if err != nil {
+			t.Fatalf("Enforce(%s, %s, %s): %v", tt.subject, tt.domain, tt.action, err)
+		}
+		if allowed != tt.allowed {
+			t.Errorf("Enforce(%s, %s, %s) = %v, expected %v", tt.subject, tt.domain, tt.action, allowed, tt.allowed)
+		}
+	}
+}
+
+func TestAddRoleInheritanceRejectsCycle(t *testing.T) {
+	ctx := context.Background()
+	casbinEnf := newTestCasbinEnforcer(t)
+
+	if err := casbinEnf.AddRoleInheritance(ctx, "admin", "editor", "domain_1"); err != nil {
+		t.Fatalf("AddRoleInheritance(admin, editor): %v", err)
+	}
+	if err := casbinEnf.AddRoleInheritance(ctx, "editor", "viewer", "domain_1"); err != nil {
+		t.Fatalf("AddRoleInheritance(editor, viewer): %v", err)
+	}
+
+	if err := casbinEnf.AddRoleInheritance(ctx, "viewer", "admin", "domain_1"); err == nil {
+		t.Error("AddRoleInheritance(viewer, admin) = nil, expected cycle error")
+	}
+	if err := casbinEnf.AddRoleInheritance(ctx, "admin", "admin", "domain_1"); err == nil {
+		t.Error("AddRoleInheritance(admin, admin) = nil, expected self inheritance error")
+	}
+	// Cycle is only checked within the domain
+	if err := casbinEnf.AddRoleInheritance(ctx, "viewer", "admin", "domain_2"); err != nil {
+		t.Errorf("AddRoleInheritance(viewer, admin) in another domain: %v", err)
+	}
+}
+
+func TestRegisterKnownFieldsWarnsOutsideEnforce(t *testing.T) {
+	ctx := context.Background()
+	casbinEnf := newTestCasbinEnforcer(t)
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	addTestPolicies(t, casbinEnf,
+		[]Policy{
+			{SubjectGroup: "member", Domain: "domain_1", Object: "report", Action: "view", Condition: `{"team_idd_eq": "t1"}`},
+		},
+		[]GroupingPolicy{
+			{Subject: "user_1", SubjectGroup: "member", Domain: "domain_1"},
+		},
+	)
+
+	casbinEnf.RegisterKnownFields([]string{"team_id"}, UNKNOWN_FIELD_MODE_WARN)
+	if n := strings.Count(logs.String(), "[warning]"); n != 1 {
+		t.Errorf("expected 1 warning of loaded policy on registering, got %d: %s", n, logs.String())
+	}
+
+	logs.Reset()
+	for i := 0; i < 3; i++ {
+		if _, err := casbinEnf.Enforce(ctx, Request{Subject: "user_1", Domain: "domain_1", Object: "report", Action: "view", CtxCondition: map[string]string{"team_id": "t1"}}); err != nil {
+			t.Fatalf("Enforce in warn mode: %v", err)
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no log on Enforce in warn mode, got: %s", logs.String())
+	}
+
+	casbinEnf.RegisterKnownFields([]string{"team_id"}, UNKNOWN_FIELD_MODE_ERROR)
+	if _, err := casbinEnf.Enforce(ctx, Request{Subject: "user_1", Domain: "domain_1", Object: "report", Action: "view"}); err == nil {
+		t.Error("Enforce in error mode = nil error, expected unknown field error")
+	}
+}
@@ -3,109 +3,358 @@ package casbinauth
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-func inScope(subject string, ctxCondition map[string]string, condition map[string]any) bool {
-	fmt.Println(subject)
-	fmt.Println(ctxCondition)
-	fmt.Println(condition)
-
+// inScopeE reports whether ctxCondition satisfies condition, it returns an error if condition is malformed
+// (e.g. "and"/"or" value is not an object or an array of objects, "_in" value is not an array).
+// "and"/"or" value can be an array of conditions, so branches with the same key can coexist
+// (e.g. {"or": [{"team_id_in": ["t1"]}, {"team_id_in": ["t2"], "role_eq": "lead"}]}).
+// All branches are evaluated, so a malformed branch is reported regardless of map ordering.
+// Evaluation of every branch is recorded into trace (nil: not recorded).
+func inScopeE(subject string, ctxCondition map[string]any, condition map[string]any, selfRefs map[string]struct{}, trace *evaluationTrace) (bool, error) {
 	if len(condition) == 0 {
-		return true
+		return true, nil
 	}
 
-	for keyCondition, valCondition := range condition {
+	result := true
+	for _, keyCondition := range sortedConditionKeys(condition) {
+		valCondition := condition[keyCondition]
 		switch keyCondition {
 		case "and":
-			subCondition, _ := valCondition.(map[string]any)
-			if !inScope(subject, ctxCondition, subCondition) {
-				return false
+			line := trace.begin()
+			var ok bool
+			switch subCondition := valCondition.(type) {
+			case map[string]any:
+				var err error
+				if ok, err = inScopeE(subject, ctxCondition, subCondition, selfRefs, trace); err != nil {
+					return false, err
+				}
+			case []any:
+				results, err := inScopeEach(subject, ctxCondition, keyCondition, subCondition, selfRefs, trace)
+				if err != nil {
+					return false, err
+				}
+				ok = true
+				for _, subOk := range results {
+					ok = ok && subOk
+				}
+			default:
+				return false, fmt.Errorf("value of 'and' must be an object or an array of objects, got %T", valCondition)
 			}
+			trace.end(line, "and -> %v", ok)
+			result = result && ok
 
 		case "or":
-			subCondition, _ := valCondition.(map[string]any)
+			line := trace.begin()
 			ok := false
-			for subKeyCondition, subValCondition := range subCondition {
-				if subKeyCondition == "and" || subKeyCondition == "or" {
-					if inScope(subject, ctxCondition, map[string]any{subKeyCondition: subValCondition}) {
-						ok = true
-						break
+			switch subCondition := valCondition.(type) {
+			case map[string]any:
+				for _, subKeyCondition := range sortedConditionKeys(subCondition) {
+					subValCondition := subCondition[subKeyCondition]
+					var subOk bool
+					var err error
+					if subKeyCondition == "and" || subKeyCondition == "or" {
+						subOk, err = inScopeE(subject, ctxCondition, map[string]any{subKeyCondition: subValCondition}, selfRefs, trace)
+					} else {
+						subOk, err = isMatched(subject, ctxCondition, subKeyCondition, subValCondition, selfRefs)
+						trace.match(subject, ctxCondition, subKeyCondition, subValCondition, selfRefs, subOk)
+					}
+					if err != nil {
+						return false, err
 					}
-					continue
+					ok = ok || subOk
 				}
-				if isMatched(subject, ctxCondition, subKeyCondition, subValCondition) {
-					ok = true
-					break
+			case []any:
+				results, err := inScopeEach(subject, ctxCondition, keyCondition, subCondition, selfRefs, trace)
+				if err != nil {
+					return false, err
 				}
+				for _, subOk := range results {
+					ok = ok || subOk
+				}
+			default:
+				return false, fmt.Errorf("value of 'or' must be an object or an array of objects, got %T", valCondition)
 			}
-			if !ok {
-				return false
-			}
+			trace.end(line, "or -> %v", ok)
+			result = result && ok
 
 		default:
-			if !isMatched(subject, ctxCondition, keyCondition, valCondition) {
-				return false
+			ok, err := isMatched(subject, ctxCondition, keyCondition, valCondition, selfRefs)
+			if err != nil {
+				return false, err
 			}
+			trace.match(subject, ctxCondition, keyCondition, valCondition, selfRefs, ok)
+			result = result && ok
 		}
 	}
 
-	return true
+	return result, nil
 }
 
-func isMatched(subject string, ctxCondition map[string]string, keyCondition string, valCondition any) bool {
-	var op string
-	field := keyCondition
-	for _, suffix := range []string{"_eq", "_in"} {
-		if strings.HasSuffix(keyCondition, suffix) {
-			op = suffix
-			field = strings.TrimSuffix(keyCondition, suffix)
-			break
+// inScopeEach evaluates every condition of "and"/"or" array (see inScopeE), returns their results in order.
+func inScopeEach(subject string, ctxCondition map[string]any, keyCondition string, conditions []any, selfRefs map[string]struct{}, trace *evaluationTrace) ([]bool, error) {
+	results := make([]bool, len(conditions))
+	for i, element := range conditions {
+		subCondition, isObject := element.(map[string]any)
+		if !isObject {
+			return nil, fmt.Errorf("element %d of '%s' must be an object, got %T", i, keyCondition, element)
+		}
+
+		line := trace.begin()
+		ok, err := inScopeE(subject, ctxCondition, subCondition, selfRefs, trace)
+		if err != nil {
+			return nil, err
 		}
+		trace.end(line, "%s[%d] -> %v", keyCondition, i, ok)
+		results[i] = ok
 	}
 
+	return results, nil
+}
+
+func isMatched(subject string, ctxCondition map[string]any, keyCondition string, valCondition any, selfRefs map[string]struct{}) (bool, error) {
+	field, op := parseConditionKey(keyCondition)
+
 	ctxValCondition, ok := ctxCondition[field]
-	if !ok || ctxValCondition == "" {
-		return true
+	if !ok || ctxValCondition == nil || ctxValCondition == "" {
+		return true, nil
 	}
 
 	switch op {
 	case "_eq":
-		return compareEq(subject, ctxValCondition, valCondition)
+		return compareEq(subject, ctxValCondition, valCondition, selfRefs), nil
 	case "_in":
-		return compareIn(ctxValCondition, valCondition)
+		return compareIn(keyCondition, ctxValCondition, valCondition)
 	default:
-		return compareEq(subject, ctxValCondition, valCondition)
+		return compareEq(subject, ctxValCondition, valCondition, selfRefs), nil
+	}
+}
+
+// compareEq compares ctxValCondition with valCondition, valCondition being a self-reference value (e.g. "owner_id") compares with subject instead.
+func compareEq(subject string, ctxValCondition any, valCondition any, selfRefs map[string]struct{}) bool {
+	if _, ok := selfRefs[stringifyCondition(valCondition)]; ok {
+		return stringifyCondition(ctxValCondition) == subject
+	} else {
+		return equalCondition(ctxValCondition, valCondition)
+	}
+}
+
+func compareIn(keyCondition string, ctxValCondition any, valCondition any) (bool, error) {
+	if valCondition == nil || reflect.TypeOf(valCondition).Kind() != reflect.Slice {
+		return false, fmt.Errorf("value of '%s' must be an array, got %T", keyCondition, valCondition)
 	}
+
+	s := reflect.ValueOf(valCondition)
+	for i := 0; i < s.Len(); i++ {
+		if equalCondition(ctxValCondition, s.Index(i).Interface()) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
-func compareEq(subject string, ctxValCondition string, valCondition any) bool {
-	var valConditionStr string
-	switch v := valCondition.(type) {
+// equalCondition compares typed values when both are numbers (e.g. int 1500 equals JSON number 1500) or both are bools,
+// otherwise compares their string forms (e.g. int 1500 equals "1500").
+func equalCondition(ctxValCondition any, valCondition any) bool {
+	if ctxNumber, ok := toFloat64(ctxValCondition); ok {
+		if number, ok := toFloat64(valCondition); ok {
+			return ctxNumber == number
+		}
+	}
+	if ctxBool, ok := ctxValCondition.(bool); ok {
+		if b, ok := valCondition.(bool); ok {
+			return ctxBool == b
+		}
+	}
+	return stringifyCondition(ctxValCondition) == stringifyCondition(valCondition)
+}
+
+// stringifyCondition returns string form of a condition value, numbers are formatted without exponent or trailing zeros
+// (e.g. float64 1500 and int 1500 are both "1500").
+func stringifyCondition(value any) string {
+	if number, ok := toFloat64(value); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+
+	switch v := value.(type) {
 	case string:
-		valConditionStr = v
+		return v
+	case bool:
+		return strconv.FormatBool(v)
 	case fmt.Stringer:
-		valConditionStr = v.String()
+		return v.String()
 	default:
-		valConditionStr = fmt.Sprintf("%v", v)
+		return fmt.Sprintf("%v", v)
 	}
+}
 
-	if valConditionStr == "owner_id" {
-		return ctxValCondition == subject
-	} else {
-		return ctxValCondition == valConditionStr
+// toFloat64 converts numeric value to float64, reports false if value is not a number.
+func toFloat64(value any) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
 	}
 }
 
-func compareIn(ctxValCondition string, valCondition any) bool {
-	switch reflect.TypeOf(valCondition).Kind() {
-	case reflect.Slice:
-		s := reflect.ValueOf(valCondition)
-		for i := 0; i < s.Len(); i++ {
-			if fmt.Sprintf("%v", s.Index(i).Interface()) == ctxValCondition {
-				return true
+// wildcardMatch reports whether value matches pattern, "*" in pattern matches any sequence of characters
+// (e.g. "user_*" matches "user_profile", "*" matches everything). Pattern without "*" must match exactly.
+func wildcardMatch(value string, pattern string) bool {
+	if !strings.Contains(pattern, "*") {
+		return value == pattern
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(value, part)
+		if index < 0 {
+			return false
+		}
+		value = value[index+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// sortedConditionKeys returns keys of condition in sorted order, so conditions are evaluated (and traced) deterministically.
+func sortedConditionKeys(condition map[string]any) []string {
+	keys := make([]string, 0, len(condition))
+	for key := range condition {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// evaluationTrace collects human-readable evaluation steps of conditions, nested steps are indented under their "and"/"or".
+// All methods do nothing on nil trace, so evaluation is not slowed down when not tracing.
+type evaluationTrace struct {
+	lines []string
+	depth int
+}
+
+// add records a step at current depth.
+func (trace *evaluationTrace) add(format string, args ...any) {
+	if trace == nil {
+		return
+	}
+	trace.lines = append(trace.lines, strings.Repeat("  ", trace.depth)+fmt.Sprintf(format, args...))
+}
+
+// begin reserves line of a step whose result is known after its nested steps, returns index of the line (see end).
+func (trace *evaluationTrace) begin() int {
+	if trace == nil {
+		return -1
+	}
+	trace.add("")
+	trace.depth++
+	return len(trace.lines) - 1
+}
+
+// end fills line reserved by begin, depth is restored to depth of the line even if nested steps were not ended.
+func (trace *evaluationTrace) end(line int, format string, args ...any) {
+	if trace == nil {
+		return
+	}
+	// Reserved line only contains its indent
+	trace.depth = len(trace.lines[line]) / 2
+	trace.lines[line] = strings.Repeat("  ", trace.depth) + fmt.Sprintf(format, args...)
+}
+
+// result returns recorded steps, lines reserved by begin but never ended (evaluation aborted by error) are dropped.
+func (trace *evaluationTrace) result() []string {
+	lines := make([]string, 0, len(trace.lines))
+	for _, line := range trace.lines {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// match records result of comparing a field (see isMatched).
+func (trace *evaluationTrace) match(subject string, ctxCondition map[string]any, keyCondition string, valCondition any, selfRefs map[string]struct{}, ok bool) {
+	if trace == nil {
+		return
+	}
+
+	field, op := parseConditionKey(keyCondition)
+	ctxValCondition, exists := ctxCondition[field]
+	switch {
+	case !exists || ctxValCondition == nil || ctxValCondition == "":
+		trace.add("%s: '%s' not in context, skipped -> %v", keyCondition, field, ok)
+	case op == "_in":
+		trace.add("%s: %v in %v -> %v", keyCondition, stringifyCondition(ctxValCondition), valCondition, ok)
+	default:
+		if _, isSelfRef := selfRefs[stringifyCondition(valCondition)]; isSelfRef {
+			trace.add("%s: %v equals subject %s -> %v", keyCondition, stringifyCondition(ctxValCondition), subject, ok)
+		} else {
+			trace.add("%s: %v equals %v -> %v", keyCondition, stringifyCondition(ctxValCondition), stringifyCondition(valCondition), ok)
+		}
+	}
+}
+
+// parseConditionKey splits condition key into field and operator (e.g. "team_id_in" -> "team_id", "_in"), operator is empty if key has no suffix.
+func parseConditionKey(keyCondition string) (string, string) {
+	for _, suffix := range []string{"_eq", "_in"} {
+		if strings.HasSuffix(keyCondition, suffix) {
+			return strings.TrimSuffix(keyCondition, suffix), suffix
+		}
+	}
+	return keyCondition, ""
+}
+
+// unknownConditionFields returns fields referenced by condition (including nested "and"/"or") which are not in knownFields, sorted and deduplicated.
+func unknownConditionFields(condition map[string]any, knownFields map[string]struct{}) []string {
+	unknown := map[string]struct{}{}
+	collectUnknownConditionFields(condition, knownFields, unknown)
+
+	fields := make([]string, 0, len(unknown))
+	for field := range unknown {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func collectUnknownConditionFields(condition map[string]any, knownFields map[string]struct{}, unknown map[string]struct{}) {
+	for keyCondition, valCondition := range condition {
+		if keyCondition == "and" || keyCondition == "or" {
+			// Malformed sub-condition is reported by inScopeE
+			switch subCondition := valCondition.(type) {
+			case map[string]any:
+				collectUnknownConditionFields(subCondition, knownFields, unknown)
+			case []any:
+				for _, element := range subCondition {
+					if elementCondition, isObject := element.(map[string]any); isObject {
+						collectUnknownConditionFields(elementCondition, knownFields, unknown)
+					}
+				}
 			}
+			continue
+		}
+
+		field, _ := parseConditionKey(keyCondition)
+		if _, ok := knownFields[field]; !ok {
+			unknown[field] = struct{}{}
 		}
 	}
-	return false
 }
@@ -0,0 +1,184 @@
+package casbinauth
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		value   string
+		pattern string
+		matched bool
+	}{
+		// Exact match
+		{"user", "user", true},
+		{"user_profile", "user", false},
+		{"", "", true},
+		// Prefix wildcard
+		{"user_profile", "user_*", true},
+		{"user_", "user_*", true},
+		{"order_item", "user_*", false},
+		{"user", "user_*", false},
+		// Full wildcard
+		{"anything", "*", true},
+		{"", "*", true},
+		// Suffix and middle wildcards
+		{"order_view", "*_view", true},
+		{"order_edit", "*_view", false},
+		{"user_1_profile", "user_*_profile", true},
+		{"user_profile", "user_*_profile", false},
+		{"a", "a*a", false},
+	}
+
+	for _, tt := range tests {
+		if matched := wildcardMatch(tt.value, tt.pattern); matched != tt.matched {
+			t.Errorf("wildcardMatch(%q, %q) = %v, expected %v", tt.value, tt.pattern, matched, tt.matched)
+		}
+	}
+}
+
+func TestEnforceWildcardObjectAndAction(t *testing.T) {
+	ctx := context.Background()
+	casbinEnf := newTestCasbinEnforcer(t)
+
+	addTestPolicies(t, casbinEnf,
+		[]Policy{
+			{SubjectGroup: "support", Domain: "domain_1", Object: "user_*", Action: "view", Condition: "*"},
+			{SubjectGroup: "admin", Domain: "domain_1", Object: "*", Action: "*", Condition: "*"},
+			{SubjectGroup: "auditor", Domain: "domain_1", Object: "report", Action: "view", Condition: "*"},
+		},
+		[]GroupingPolicy{
+			{Subject: "user_support", SubjectGroup: "support", Domain: "domain_1"},
+			{Subject: "user_admin", SubjectGroup: "admin", Domain: "domain_1"},
+			{Subject: "user_auditor", SubjectGroup: "auditor", Domain: "domain_1"},
+		},
+	)
+
+	tests := []struct {
+		subject string
+		object  string
+		action  string
+		allowed bool
+	}{
+		{"user_support", "user_profile", "view", true},
+		{"user_support", "user_profile", "edit", false},
+		{"user_support", "order", "view", false},
+		{"user_admin", "order", "delete", true},
+		{"user_auditor", "report", "view", true},
+		{"user_auditor", "report_2024", "view", false},
+	}
+	for _, tt := range tests {
+		allowed, err := casbinEnf.Enforce(ctx, Request{Subject: tt.subject, Domain: "domain_1", Object: tt.object, Action: tt.action})
+		if err != nil {
+			t.Fatalf("Enforce(%s, %s, %s): %v", tt.subject, tt.object, tt.action, err)
+		}
+		if allowed != tt.allowed {
+			t.Errorf("Enforce(%s, %s, %s) = %v, expected %v", tt.subject, tt.object, tt.action, allowed, tt.allowed)
+		}
+	}
+}
+
+// parseTestCondition unmarshals JSON condition of a policy, failing the test on error.
+func parseTestCondition(t *testing.T, rawCondition string) map[string]any {
+	t.Helper()
+
+	var condition map[string]any
+	if err := json.Unmarshal([]byte(rawCondition), &condition); err != nil {
+		t.Fatalf("unmarshal condition %s: %v", rawCondition, err)
+	}
+	return condition
+}
+
+func TestInScopeEConditionArrays(t *testing.T) {
+	// Both "or" branches constrain team_id_in, which an object can't express as keys must be unique
+	orCondition := `{"or": [{"team_id_in": ["t1"]}, {"team_id_in": ["t2"], "role_eq": "lead"}]}`
+	andCondition := `{"and": [{"team_id_in": ["t1", "t2"]}, {"team_id_in": ["t2", "t3"]}]}`
+
+	tests := []struct {
+		name         string
+		condition    string
+		ctxCondition map[string]any
+		inScope      bool
+	}{
+		{"or first branch", orCondition, map[string]any{"team_id": "t1", "role": "member"}, true},
+		{"or second branch", orCondition, map[string]any{"team_id": "t2", "role": "lead"}, true},
+		{"or second branch role mismatch", orCondition, map[string]any{"team_id": "t2", "role": "member"}, false},
+		{"or no branch", orCondition, map[string]any{"team_id": "t3", "role": "lead"}, false},
+		{"and both branches", andCondition, map[string]any{"team_id": "t2"}, true},
+		{"and first branch only", andCondition, map[string]any{"team_id": "t1"}, false},
+		{"and second branch only", andCondition, map[string]any{"team_id": "t3"}, false},
+		{"empty or array", `{"or": []}`, map[string]any{"team_id": "t1"}, false},
+		{"empty and array", `{"and": []}`, map[string]any{"team_id": "t1"}, true},
+	}
+
+	selfRefs := map[string]struct{}{defaultSelfReferenceValue: {}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inScope, err := inScopeE("user_1", tt.ctxCondition, parseTestCondition(t, tt.condition), selfRefs, nil)
+			if err != nil {
+				t.Fatalf("inScopeE: %v", err)
+			}
+			if inScope != tt.inScope {
+				t.Errorf("inScopeE(%v, %s) = %v, expected %v", tt.ctxCondition, tt.condition, inScope, tt.inScope)
+			}
+		})
+	}
+}
+
+func TestInScopeEMalformedConditionArrays(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition string
+		errMsg    string
+	}{
+		{"or element not object", `{"or": [{"team_id_in": ["t1"]}, "t2"]}`, "element 1 of 'or' must be an object, got string"},
+		{"and element not object", `{"and": [["t1"]]}`, "element 0 of 'and' must be an object, got []interface {}"},
+		{"or value not object or array", `{"or": "t1"}`, "value of 'or' must be an object or an array of objects, got string"},
+		{"malformed nested in element", `{"or": [{"team_id_in": "t1"}]}`, "value of 'team_id_in' must be an array, got string"},
+		// A branch matching first doesn't hide a malformed branch after it
+		{"malformed after matching element", `{"or": [{"team_id_in": ["t1"]}, {"team_id_in": "t2"}]}`, "value of 'team_id_in' must be an array, got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := inScopeE("user_1", map[string]any{"team_id": "t1"}, parseTestCondition(t, tt.condition), nil, nil)
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("inScopeE(%s) error = %v, expected %q", tt.condition, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestEnforceConditionArray(t *testing.T) {
+	ctx := context.Background()
+	casbinEnf := newTestCasbinEnforcer(t)
+
+	addTestPolicies(t, casbinEnf,
+		[]Policy{
+			{SubjectGroup: "member", Domain: "domain_1", Object: "report", Action: "view", Condition: `{"or": [{"team_id_in": ["t1"]}, {"team_id_in": ["t2"], "role_eq": "lead"}]}`},
+		},
+		[]GroupingPolicy{
+			{Subject: "user_1", SubjectGroup: "member", Domain: "domain_1"},
+		},
+	)
+
+	tests := []struct {
+		ctxCondition map[string]string
+		allowed      bool
+	}{
+		{map[string]string{"team_id": "t1", "role": "member"}, true},
+		{map[string]string{"team_id": "t2", "role": "lead"}, true},
+		{map[string]string{"team_id": "t2", "role": "member"}, false},
+	}
+	for _, tt := range tests {
+		allowed, err := casbinEnf.Enforce(ctx, Request{Subject: "user_1", Domain: "domain_1", Object: "report", Action: "view", CtxCondition: tt.ctxCondition})
+		if err != nil {
+			t.Fatalf("Enforce(%v): %v", tt.ctxCondition, err)
+		}
+		if allowed != tt.allowed {
+			t.Errorf("Enforce(%v) = %v, expected %v", tt.ctxCondition, allowed, tt.allowed)
+		}
+	}
+}
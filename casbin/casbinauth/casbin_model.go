@@ -16,11 +16,24 @@ func (CustomCasbinRule) TableName() string {
 }
 
 type Request struct {
-	Subject      string
-	Domain       string
-	Object       string
-	Action       string
-	CtxCondition map[string]string
+	Subject         string
+	Domain          string
+	Object          string
+	Action          string
+	CtxCondition    map[string]string
+	CtxConditionAny map[string]any // Typed context (e.g. amount: 1500), it takes precedence over CtxCondition on the same key
+}
+
+// ctxCondition merges CtxCondition and CtxConditionAny into the context passed to matcher.
+func (request Request) ctxCondition() map[string]any {
+	ctxCondition := make(map[string]any, len(request.CtxCondition)+len(request.CtxConditionAny))
+	for key, value := range request.CtxCondition {
+		ctxCondition[key] = value
+	}
+	for key, value := range request.CtxConditionAny {
+		ctxCondition[key] = value
+	}
+	return ctxCondition
 }
 
 type Policy struct {
@@ -36,3 +49,21 @@ type GroupingPolicy struct {
 	SubjectGroup string
 	Domain       string
 }
+
+// DenyReason is a machine-readable reason of a denied EnforceEx decision.
+type DenyReason string
+
+const (
+	DENY_REASON_NONE               DenyReason = ""                   // Request is allowed
+	DENY_REASON_NO_MATCHING_POLICY DenyReason = "no_matching_policy" // No policy of domain matches object and action
+	DENY_REASON_ROLE_NOT_ASSIGNED  DenyReason = "role_not_assigned"  // Some policy matches object and action, but subject doesn't have its role
+	DENY_REASON_CONDITION_FAILED   DenyReason = "condition_failed"   // Some policy of subject matches object and action, but its condition is not satisfied
+)
+
+// UnknownFieldMode is how a condition referencing a field not registered by RegisterKnownFields is handled.
+type UnknownFieldMode string
+
+const (
+	UNKNOWN_FIELD_MODE_WARN  UnknownFieldMode = "warn"  // Log a warning on registering and adding policy, condition is still evaluated
+	UNKNOWN_FIELD_MODE_ERROR UnknownFieldMode = "error" // Reject policy on add and fail Enforce with error
+)
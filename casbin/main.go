@@ -19,7 +19,7 @@ func main() {
 		log.Fatalf("Failed to connect to Postgres: %v", err)
 	}
 
-	casbinauth.CasbinEnforcerInstance = casbinauth.NewCasbinEnforcer("config/hybrid_model.conf", db)
+	casbinauth.CasbinEnforcerInstance = casbinauth.MustNewCasbinEnforcer("config/hybrid_model.conf", db)
 
 	// testSetupRole()
 	// testPrintRole()
@@ -1,13 +1,48 @@
 package apperror
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	constants "thanhldt060802/common/contants"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 )
 
+// Sentinels of error categories, use with errors.Is to check category of a *CustomError.
+//
+// Example:
+//
+//	if errors.Is(err, apperror.ErrNotFoundSentinel) {
+//	    ...
+//	}
+var (
+	ErrBadRequestSentinel          = errors.New("bad request")
+	ErrUnauthorizedSentinel        = errors.New("unauthorized")
+	ErrForbiddenSentinel           = errors.New("forbidden")
+	ErrNotFoundSentinel            = errors.New("not found")
+	ErrConflictSentinel            = errors.New("conflict")
+	ErrTooManyRequestsSentinel     = errors.New("too many requests")
+	ErrInternalServerErrorSentinel = errors.New("internal server error")
+	ErrServiceUnavailableSentinel  = errors.New("service unavailable")
+	ErrGatewayTimeoutSentinel      = errors.New("gateway timeout")
+)
+
+var mapStatusSentinel = map[int]error{
+	http.StatusBadRequest:          ErrBadRequestSentinel,
+	http.StatusUnauthorized:        ErrUnauthorizedSentinel,
+	http.StatusForbidden:           ErrForbiddenSentinel,
+	http.StatusNotFound:            ErrNotFoundSentinel,
+	http.StatusConflict:            ErrConflictSentinel,
+	http.StatusTooManyRequests:     ErrTooManyRequestsSentinel,
+	http.StatusInternalServerError: ErrInternalServerErrorSentinel,
+	http.StatusServiceUnavailable:  ErrServiceUnavailableSentinel,
+	http.StatusGatewayTimeout:      ErrGatewayTimeoutSentinel,
+}
+
 type CustomError struct {
 	error
 
@@ -16,6 +51,9 @@ type CustomError struct {
 	Message  string   `json:"message"`
 	ErrorMsg string   `json:"error,omitempty"`
 	Details  []string `json:"details,omitempty"`
+
+	Retryable  bool          `json:"retryable"`
+	RetryAfter time.Duration `json:"-"`
 }
 
 func NewCustomError(status int, code string, message string, errs ...error) huma.StatusError {
@@ -47,6 +85,33 @@ func (e *CustomError) GetStatus() int {
 	return e.Status
 }
 
+// Unwrap returns the wrapped error, so errors.Is and errors.As can reach it.
+func (e *CustomError) Unwrap() error {
+	return e.error
+}
+
+// Is reports whether target is the sentinel of the error category (by status).
+func (e *CustomError) Is(target error) bool {
+	sentinel, ok := mapStatusSentinel[e.Status]
+	return ok && sentinel == target
+}
+
+// GetHeaders returns Retry-After header (in seconds) when RetryAfter is set, huma writes it to the error response.
+func (e *CustomError) GetHeaders() http.Header {
+	headers := http.Header{}
+	if e.RetryAfter > 0 {
+		headers.Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+	return headers
+}
+
+// WithRetryAfter marks the error as retryable after the given duration.
+func (e *CustomError) WithRetryAfter(retryAfter time.Duration) *CustomError {
+	e.Retryable = true
+	e.RetryAfter = retryAfter
+	return e
+}
+
 func HandleError(err error) huma.StatusError {
 	errorCode := "ERR_SYSTEM_ERROR"
 	errorCodeMessage := http.StatusText(http.StatusInternalServerError)
@@ -97,6 +162,31 @@ func ErrServiceUnavailable(err error, message string, details ...string) huma.St
 		Code:     string(constants.ERR_SERVICE_UNAVAILABLE),
 		ErrorMsg: fmt.Sprintf("%s: %s", constants.ERR_SERVICE_UNAVAILABLE, message),
 		Details:  details,
+
+		Retryable: true,
+	}
+}
+
+func ErrTooManyRequests(message string, details ...string) *CustomError {
+	return &CustomError{
+		Status:   http.StatusTooManyRequests,
+		Message:  message,
+		Code:     string(constants.ERR_TOO_MANY_REQUESTS),
+		ErrorMsg: fmt.Sprintf("%s: %s", constants.ERR_TOO_MANY_REQUESTS, message),
+		Details:  details,
+
+		Retryable: true,
+	}
+}
+
+func ErrGatewayTimeout(err error, message string, details ...string) *CustomError {
+	return &CustomError{
+		error:    err,
+		Status:   http.StatusGatewayTimeout,
+		Message:  message,
+		Code:     string(constants.ERR_GATEWAY_TIMEOUT),
+		ErrorMsg: fmt.Sprintf("%s: %s", constants.ERR_GATEWAY_TIMEOUT, message),
+		Details:  details,
 	}
 }
 
@@ -0,0 +1,54 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCustomErrorIsCategorySentinel(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"not found", ErrNotFound("user not found", "ERR_USER_NOT_FOUND"), ErrNotFoundSentinel},
+		{"conflict", ErrConflict("user exists", "ERR_USER_EXISTS"), ErrConflictSentinel},
+		{"bad request", ErrBadRequest("invalid id"), ErrBadRequestSentinel},
+		{"gateway timeout", ErrGatewayTimeout(errors.New("upstream timeout"), "timeout"), ErrGatewayTimeoutSentinel},
+		{"internal server error", ErrInternalServerError(sql.ErrConnDone, "db failed", "ERR_DB"), ErrInternalServerErrorSentinel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("get user: %w", tt.err)
+			if !errors.Is(wrapped, tt.sentinel) {
+				t.Errorf("errors.Is(%v, %v) = false, expected true", wrapped, tt.sentinel)
+			}
+			if errors.Is(wrapped, ErrForbiddenSentinel) {
+				t.Errorf("errors.Is(%v, ErrForbiddenSentinel) = true, expected false", wrapped)
+			}
+		})
+	}
+}
+
+func TestCustomErrorIsWrappedError(t *testing.T) {
+	err := fmt.Errorf("get user: %w", ErrInternalServerError(sql.ErrNoRows, "db failed", "ERR_DB"))
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("errors.Is(%v, sql.ErrNoRows) = false, expected true", err)
+	}
+}
+
+func TestCustomErrorAs(t *testing.T) {
+	err := fmt.Errorf("handler: %w", fmt.Errorf("service: %w", ErrNotFound("user not found", "ERR_USER_NOT_FOUND")))
+
+	var customErr *CustomError
+	if !errors.As(err, &customErr) {
+		t.Fatalf("errors.As(%v, *CustomError) = false, expected true", err)
+	}
+	if customErr.Code != "ERR_USER_NOT_FOUND" || customErr.GetStatus() != 404 {
+		t.Errorf("extracted error = %q (%d), expected ERR_USER_NOT_FOUND (404)", customErr.Code, customErr.GetStatus())
+	}
+}
@@ -21,6 +21,8 @@ var (
 	ERR_UNAUTHORIZED             ERROR_CODE = "ERR_UNAUTHORIZED"
 	ERR_FORBIDDEN                ERROR_CODE = "ERR_FORBIDDEN"
 	ERR_SERVICE_UNAVAILABLE      ERROR_CODE = "ERR_SERVICE_UNAVAILABLE"
+	ERR_TOO_MANY_REQUESTS        ERROR_CODE = "ERR_TOO_MANY_REQUESTS"
+	ERR_GATEWAY_TIMEOUT          ERROR_CODE = "ERR_GATEWAY_TIMEOUT"
 	ERR_INTERNAL_SERVER_ERROR    ERROR_CODE = "ERR_INTERNAL_SERVER_ERROR"
 	ERR_NOT_IMPLEMENTED          ERROR_CODE = "ERR_NOT_IMPLEMENTED"
 	ERR_NOT_IMPLEMENT_FOR_MASTER ERROR_CODE = "ERR_NOT_IMPLEMENT_FOR_MASTER"
@@ -152,6 +154,8 @@ var MAP_ERROR_CODE = map[ERROR_CODE]string{
 	ERR_UNAUTHORIZED:                       "unauthorized",
 	ERR_FORBIDDEN:                          "forbidden",
 	ERR_SERVICE_UNAVAILABLE:                "service unavailable",
+	ERR_TOO_MANY_REQUESTS:                  "too many requests",
+	ERR_GATEWAY_TIMEOUT:                    "gateway timeout",
 	ERR_INTERNAL_SERVER_ERROR:              "internal server error",
 	ERR_NOT_IMPLEMENTED:                    "not implemented",
 	ERR_NOT_IMPLEMENT_FOR_MASTER:           "not implemented for master",
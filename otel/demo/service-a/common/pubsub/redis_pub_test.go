@@ -0,0 +1,128 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"thanhldt060802/model"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+// newFakeRedisServer starts a TCP server speaking enough RESP for publishing, every PUBLISH is answered with 0 receivers.
+// Each command still costs a network round-trip, so Publish in a loop and PublishBatch can be compared without Redis.
+func newFakeRedisServer(tb testing.TB) string {
+	tb.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("listen: %v", err)
+	}
+	tb.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeRedisConn(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// serveFakeRedisConn replies to each command of conn, HELLO is rejected so the client falls back to RESP2.
+func serveFakeRedisConn(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		args, err := readFakeRedisCommand(reader)
+		if err != nil {
+			return
+		}
+
+		reply := ":0\r\n"
+		if strings.EqualFold(args[0], "HELLO") {
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readFakeRedisCommand reads a command sent as RESP array of bulk strings.
+func readFakeRedisCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil || count < 1 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		arg := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, arg); err != nil {
+			return nil, err
+		}
+		args[i] = string(arg[:size])
+	}
+	return args, nil
+}
+
+func BenchmarkPublish(b *testing.B) {
+	logOutput := log.StandardLogger().Out
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(logOutput) })
+
+	client := redis.NewClient(&redis.Options{Addr: newFakeRedisServer(b)})
+	b.Cleanup(func() { client.Close() })
+	redisPub := NewRedisPub[*model.ExamplePubSubMessage](client)
+
+	ctx := context.Background()
+	for _, batchSize := range []int{10, 100} {
+		messages := make([]*model.ExamplePubSubMessage, batchSize)
+		for i := range messages {
+			messages[i] = &model.ExamplePubSubMessage{ExampleUuid: "example-" + strconv.Itoa(i)}
+		}
+
+		b.Run("Loop/"+strconv.Itoa(batchSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				for _, message := range messages {
+					if err := redisPub.Publish(ctx, "example", message); err != nil {
+						b.Fatalf("Publish: %v", err)
+					}
+				}
+			}
+		})
+
+		b.Run("Batch/"+strconv.Itoa(batchSize), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if err := redisPub.PublishBatch(ctx, "example", messages); err != nil {
+					b.Fatalf("PublishBatch: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -5,9 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
-
-	"github.com/redis/go-redis/v9"
 )
 
 // Error definitions for Cache.
@@ -22,7 +21,13 @@ type Cache interface {
 	setTraceCarrierFromGroup(group string, key string, traceCarrier TraceCarrier) error
 	deleteTraceCarrierFromGroup(group string, key string) error
 	deleteTraceCarrierGroup(group string) error
+	deleteTraceCarrierGroupAndNotify(group string, channel string) error
 	clearTraceCarrier() error
+	clearTraceCarrierOlderThan(age time.Duration) (int, error)
+	listKeysInGroup(group string) ([]string, error)
+	countGroup(group string) (int64, error)
+	countGroups(ctx context.Context) (map[string]int64, error)
+	ping(ctx context.Context) error
 }
 
 // RedisConfig configures Redis connection for trace context storage.
@@ -37,12 +42,36 @@ type RedisConfig struct {
 	ReadTimeoutSec  int    // Redis connection pool read timeout second
 	WriteTimeoutSec int    // Redis connection pool write timeout second
 	Channel         string // Collection of keys managed
+	CarrierTTLSec   int    // Time to live second of Trace Carrier group, refreshed on every set (0: no expiration)
 }
 
-// redisCache implements Cache using Redis Cache
+// redisCache implements Cache using RedisGroupCache of Trace Carriers
 type redisCache struct {
-	redisClient *redis.Client
-	channel     string
+	carriers *RedisGroupCache[storedTraceCarrier]
+}
+
+// storedTraceCarrier is a Trace Carrier stored in Cache with its creation time,
+// since Redis hash fields don't have their own timestamps.
+type storedTraceCarrier struct {
+	Carrier   TraceCarrier `json:"carrier"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// UnmarshalJSON also accepts a bare Trace Carrier stored before creation time was kept, its CreatedAt is zero.
+func (stored *storedTraceCarrier) UnmarshalJSON(data []byte) error {
+	type plain storedTraceCarrier
+	var value plain
+	if err := json.Unmarshal(data, &value); err == nil && !value.CreatedAt.IsZero() {
+		*stored = storedTraceCarrier(value)
+		return nil
+	}
+
+	var carrier TraceCarrier
+	if err := json.Unmarshal(data, &carrier); err != nil {
+		return err
+	}
+	*stored = storedTraceCarrier{Carrier: carrier}
+	return nil
 }
 
 // Default Redis settings
@@ -62,116 +91,135 @@ const (
 // Key prefix for Cache Trace Carriers
 const traceCarrierRedisCacheKey = "OTEL:TRACECARRIER"
 
-// initRedisCache initializes Redis connection and sets the global Cache
-func initRedisCache(config *RedisConfig) *redisCache {
-	// Create Redis client
-	redisClient := redis.NewClient(&redis.Options{
-		Addr:            config.Address,
-		Username:        config.Username,
-		Password:        config.Password,
-		DB:              config.Database,
-		PoolSize:        config.PoolSize,
-		PoolTimeout:     time.Duration(config.PoolTimeoutSec) * time.Second,
-		ConnMaxIdleTime: time.Duration(config.IdleTimeoutSec) * time.Second,
-		ReadTimeout:     time.Duration(config.ReadTimeoutSec) * time.Second,
-		WriteTimeout:    time.Duration(config.WriteTimeoutSec) * time.Second,
-	})
-
-	// Ping to Redis for connection checking
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if _, err := redisClient.Ping(ctx).Result(); err != nil {
-		stdLog.Fatalf("[error] Failed to ping to Redis: %v", err)
+// Validate checks required fields and values of RedisConfig.
+func (config *RedisConfig) Validate() error {
+	if config.Address == "" {
+		return errors.New("address is required")
 	}
-
-	// Init redisCache
-	cache := &redisCache{
-		redisClient: redisClient,
-		channel:     config.Channel,
+	if config.CarrierTTLSec < 0 {
+		return fmt.Errorf("carrier ttl %d must be non-negative", config.CarrierTTLSec)
 	}
-
-	// Return redisCache
-	return cache
+	if err := validateCacheChannel(config.Channel); err != nil {
+		return err
+	}
+	return nil
 }
 
-// getChannelKey constructs the full Redis key for all Trace Carrier in a channel
-func (rCache *redisCache) getChannelKey() string {
-	return traceCarrierRedisCacheKey + ":" + rCache.channel
+// setDefaults fills unset pool settings of RedisConfig with default values.
+func (config *RedisConfig) setDefaults() {
+	if config.PoolSize <= 0 {
+		config.PoolSize = defaultRedisPoolSize
+	}
+	if config.PoolTimeoutSec <= 0 {
+		config.PoolTimeoutSec = defaultRedisPoolTimeoutSec
+	}
+	if config.IdleTimeoutSec <= 0 {
+		config.IdleTimeoutSec = defaultRedisIdleTimeoutSec
+	}
+	if config.ReadTimeoutSec <= 0 {
+		config.ReadTimeoutSec = defaultRedisReadTimeoutSec
+	}
+	if config.WriteTimeoutSec <= 0 {
+		config.WriteTimeoutSec = defaultRedisWriteTimeoutSec
+	}
 }
 
-// getGroupKey constructs the full Redis key for a Trace Carrier group
-func (rCache *redisCache) getGroupKey(group string) string {
-	return rCache.getChannelKey() + ":" + group
+// validateCacheChannel checks channel is usable as a segment of cache keys,
+// key separator and glob characters would make keys ambiguous and SCAN patterns over-match other channels.
+func validateCacheChannel(channel string) error {
+	if channel == "" {
+		return errors.New("channel is required")
+	}
+	if strings.ContainsAny(channel, ":*?[]") {
+		return fmt.Errorf("channel '%s' must not contain any of ':*?[]'", channel)
+	}
+	return nil
 }
 
-// getTraceCarrierFromGroup retrieves a Trace Carrier from Redis hash
-func (rCache *redisCache) getTraceCarrierFromGroup(group string, key string) (TraceCarrier, error) {
-	rawValue, err := rCache.redisClient.HGet(context.Background(), rCache.getGroupKey(group), key).Result()
-
+// initRedisCache initializes Redis connection and sets the global Cache
+func initRedisCache(config *RedisConfig) (*redisCache, error) {
+	carriers, err := NewRedisGroupCache[storedTraceCarrier](config, traceCarrierRedisCacheKey)
 	if err != nil {
-		// Return empty carrier for non-existent keys
-		if err == redis.Nil {
-			return TraceCarrier{}, nil
-		}
-		return TraceCarrier{}, err
+		return nil, err
 	}
 
-	var carrier TraceCarrier
-	if err := json.Unmarshal([]byte(rawValue), &carrier); err != nil {
+	return &redisCache{carriers: carriers}, nil
+}
+
+// getTraceCarrierFromGroup retrieves a Trace Carrier from Redis hash.
+// Expired group is removed by Redis, so its Trace Carriers are returned as not found.
+func (rCache *redisCache) getTraceCarrierFromGroup(group string, key string) (TraceCarrier, error) {
+	// Return empty carrier for non-existent keys
+	stored, found, err := rCache.carriers.Get(context.Background(), group, key)
+	if err != nil || !found {
 		return TraceCarrier{}, err
 	}
-
-	return carrier, nil
+	return stored.Carrier, nil
 }
 
-// setTraceCarrierFromGroup stores a Trace Carrier in Redis.
+// setTraceCarrierFromGroup stores a Trace Carrier in Redis hash and refreshes TTL of the group if configured.
 func (rCache *redisCache) setTraceCarrierFromGroup(group string, key string, traceCarrier TraceCarrier) error {
-	byteValue, err := json.Marshal(traceCarrier)
-	if err != nil {
-		return err
-	}
-
-	return rCache.redisClient.HSet(context.Background(), rCache.getGroupKey(group), key, string(byteValue)).Err()
+	return rCache.carriers.Set(context.Background(), group, key, storedTraceCarrier{
+		Carrier:   traceCarrier,
+		CreatedAt: time.Now(),
+	})
 }
 
 // deleteTraceCarrierFromGroup removes a specific Trace Carrier from Redis.
 func (rCache *redisCache) deleteTraceCarrierFromGroup(group string, key string) error {
-	return rCache.redisClient.HDel(context.Background(), rCache.getGroupKey(group), key).Err()
+	return rCache.carriers.Delete(context.Background(), group, key)
 }
 
 // deleteTraceCarrierGroup removes an entire group of Trace Carriers.
 func (rCache *redisCache) deleteTraceCarrierGroup(group string) error {
-	return rCache.redisClient.Del(context.Background(), rCache.getGroupKey(group)).Err()
+	return rCache.carriers.DeleteGroup(context.Background(), group)
+}
+
+// deleteTraceCarrierGroupAndNotify removes an entire group of Trace Carriers and publishes group name on Redis channel.
+func (rCache *redisCache) deleteTraceCarrierGroupAndNotify(group string, channel string) error {
+	return rCache.carriers.deleteGroupAndNotify(context.Background(), group, channel)
 }
 
 // clearTraceCarrier removes all groups of Trace Carriers.
 func (rCache *redisCache) clearTraceCarrier() error {
-	ctx := context.Background()
+	return rCache.carriers.Clear(context.Background())
+}
 
-	var cursor uint64
-	pattern := fmt.Sprintf("%s*", rCache.getChannelKey())
-	keys := make([]string, 0)
+// clearTraceCarrierOlderThan removes Trace Carriers created more than age ago, returns number of removed Trace Carriers.
+// Trace Carriers stored without creation time are treated as stale.
+func (rCache *redisCache) clearTraceCarrierOlderThan(age time.Duration) (int, error) {
+	threshold := time.Now().Add(-age)
+	return rCache.carriers.DeleteWhere(context.Background(), func(group string, key string, stored storedTraceCarrier) bool {
+		return stored.CreatedAt.Before(threshold)
+	})
+}
 
-	for {
-		existingKeys, nextCursor, err := rCache.redisClient.Scan(context.Background(), cursor, pattern, 100).Result()
-		if err != nil {
-			stdLog.Printf("[error] Failed to scan partten '%s' with cursor '%d': %v", pattern, cursor, err)
-		}
-		keys = append(keys, existingKeys...)
+// listKeysInGroup lists keys of all Trace Carriers in a group.
+// Returns empty slice for non-existent group.
+func (rCache *redisCache) listKeysInGroup(group string) ([]string, error) {
+	return rCache.carriers.Keys(context.Background(), group)
+}
 
-		cursor = nextCursor
-		if cursor == 0 {
-			break
-		}
-	}
+// countGroup counts Trace Carriers in a group.
+// Returns zero for non-existent group.
+func (rCache *redisCache) countGroup(group string) (int64, error) {
+	return rCache.carriers.Count(context.Background(), group)
+}
 
-	return rCache.redisClient.Del(ctx, keys...).Err()
+// countGroups counts Trace Carriers of every group.
+func (rCache *redisCache) countGroups(ctx context.Context) (map[string]int64, error) {
+	return rCache.carriers.CountGroups(ctx)
+}
+
+// ping checks connectivity to Redis.
+func (rCache *redisCache) ping(ctx context.Context) error {
+	return rCache.carriers.Ping(ctx)
 }
 
 // Public API functions with nil-safety checks.
 
 // GetCacheTraceCarrierFromGroup retrieves a Trace Carrier from Cache.
-// Returns ErrRedisUnconfigured if Redis was not initialized.
+// Returns ErrCacheUnconfigured if Cache was not configured.
 //
 // Example:
 //
@@ -188,7 +236,7 @@ func (o *Observer) GetCacheTraceCarrierFromGroup(group string, key string) (Trac
 }
 
 // SetCacheTraceCarrierFromGroup stores a Trace Carrier in Cache.
-// Returns ErrRedisUnconfigured if Redis was not initialized.
+// Returns ErrCacheUnconfigured if Cache was not configured.
 //
 // Example:
 //
@@ -203,7 +251,7 @@ func (o *Observer) SetCacheTraceCarrierFromGroup(group string, key string, trace
 }
 
 // DeleteCacheTraceCarrierFromGroup removes a Trace Carrier from Cache.
-// Returns ErrRedisUnconfigured if Redis was not initialized.
+// Returns ErrCacheUnconfigured if Cache was not configured.
 //
 // Example:
 //
@@ -217,7 +265,7 @@ func (o *Observer) DeleteCacheTraceCarrierFromGroup(group string, key string) er
 }
 
 // DeleteCacheTraceCarrierGroup removes all Trace Carriers in a group.
-// Returns ErrRedisUnconfigured if Redis was not initialized.
+// Returns ErrCacheUnconfigured if Cache was not configured.
 //
 // Example:
 //
@@ -230,8 +278,23 @@ func (o *Observer) DeleteCacheTraceCarrierGroup(group string) error {
 	return o.cache.deleteTraceCarrierGroup(group)
 }
 
+// DeleteCacheTraceCarrierGroupAndNotify removes all Trace Carriers in a group and publishes the group name on Redis channel,
+// so consumers waiting for the group (e.g. completion of a batch of async jobs) are woken up.
+// Returns ErrCacheUnconfigured if Cache was not configured.
+//
+// Example:
+//
+//	err := observer.DeleteCacheTraceCarrierGroupAndNotify("jobs", "jobs-completed")
+func (o *Observer) DeleteCacheTraceCarrierGroupAndNotify(group string, channel string) error {
+	if o.cache == nil {
+		return ErrCacheUnconfigured
+	}
+
+	return o.cache.deleteTraceCarrierGroupAndNotify(group, channel)
+}
+
 // ClearCacheTraceCarrier removes all groups of Trace Carriers.
-// Returns ErrRedisUnconfigured if Redis was not initialized.
+// Returns ErrCacheUnconfigured if Cache was not configured.
 //
 // Example:
 //
@@ -243,3 +306,51 @@ func (o *Observer) ClearCacheTraceCarrier() error {
 
 	return o.cache.clearTraceCarrier()
 }
+
+// ClearCacheTraceCarrierOlderThan removes Trace Carriers created more than age ago in all groups, e.g. orphaned carriers
+// whose consumer never picked them up. Returns number of removed Trace Carriers.
+// Returns ErrCacheUnconfigured if Cache was not configured.
+//
+// Example:
+//
+//	removed, err := observer.ClearCacheTraceCarrierOlderThan(time.Hour)
+func (o *Observer) ClearCacheTraceCarrierOlderThan(age time.Duration) (int, error) {
+	if o.cache == nil {
+		return 0, ErrCacheUnconfigured
+	}
+	if age < 0 {
+		return 0, fmt.Errorf("age %v must be non-negative", age)
+	}
+
+	return o.cache.clearTraceCarrierOlderThan(age)
+}
+
+// ListCacheTraceCarrierKeysInGroup lists keys of all Trace Carriers in a group.
+// Returns empty slice for non-existent group.
+// Returns ErrCacheUnconfigured if Cache was not configured.
+//
+// Example:
+//
+//	keys, err := observer.ListCacheTraceCarrierKeysInGroup("jobs")
+func (o *Observer) ListCacheTraceCarrierKeysInGroup(group string) ([]string, error) {
+	if o.cache == nil {
+		return []string{}, ErrCacheUnconfigured
+	}
+
+	return o.cache.listKeysInGroup(group)
+}
+
+// CountCacheTraceCarrierGroup counts Trace Carriers in a group.
+// Returns zero for non-existent group.
+// Returns ErrCacheUnconfigured if Cache was not configured.
+//
+// Example:
+//
+//	count, err := observer.CountCacheTraceCarrierGroup("jobs")
+func (o *Observer) CountCacheTraceCarrierGroup(group string) (int64, error) {
+	if o.cache == nil {
+		return 0, ErrCacheUnconfigured
+	}
+
+	return o.cache.countGroup(group)
+}
@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Diagnostics is a snapshot of Observer internals for debugging (e.g. served by an internal stats endpoint).
+// Sections of features which are not configured are nil and omitted from JSON.
+type Diagnostics struct {
+	Cache  *CacheDiagnostics `json:"cache,omitempty"`  // Cache of Trace Carriers (nil if Cache is not configured)
+	Gauges []GaugeSnapshot   `json:"gauges,omitempty"` // Current gauge values (nil if Meter is not configured)
+}
+
+// CacheDiagnostics is a snapshot of Cache of Trace Carriers.
+type CacheDiagnostics struct {
+	GroupCounts map[string]int64 `json:"group_counts"`    // Number of Trace Carriers per group
+	Error       string           `json:"error,omitempty"` // Error of reading Cache, group counts may be partial
+}
+
+// GaugeSnapshot is the current value of a gauge for one attribute set.
+type GaugeSnapshot struct {
+	Name      MetricName     `json:"name"`
+	Value     float64        `json:"value"`
+	Attrs     map[string]any `json:"attrs,omitempty"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func newGaugeSnapshot(name MetricName, value float64, attrs []attribute.KeyValue, updatedAt time.Time) GaugeSnapshot {
+	snapshot := GaugeSnapshot{
+		Name:      name,
+		Value:     value,
+		UpdatedAt: updatedAt,
+	}
+	if len(attrs) > 0 {
+		snapshot.Attrs = make(map[string]any, len(attrs))
+		for _, attr := range attrs {
+			snapshot.Attrs[string(attr.Key)] = attr.Value.AsInterface()
+		}
+	}
+	return snapshot
+}
+
+// DiagnosticsSnapshot returns counts of Trace Carriers per group in Cache and current gauge values.
+// A failure of reading Cache is reported in the Cache section instead of failing the whole snapshot.
+//
+// Example:
+//
+//	r.GET("/internal/stats", func(c *gin.Context) {
+//	    c.JSON(http.StatusOK, observer.DiagnosticsSnapshot(c.Request.Context()))
+//	})
+func (o *Observer) DiagnosticsSnapshot(ctx context.Context) Diagnostics {
+	diagnostics := Diagnostics{}
+
+	if o.cache != nil {
+		groupCounts, err := o.cache.countGroups(ctx)
+		diagnostics.Cache = &CacheDiagnostics{
+			GroupCounts: groupCounts,
+		}
+		if err != nil {
+			diagnostics.Cache.Error = err.Error()
+		}
+	}
+
+	if o.metricCollectorManager != nil {
+		diagnostics.Gauges = o.metricCollectorManager.gaugeSnapshots()
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,172 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Error sampling settings.
+const (
+	// erroredTraceTTL is how long a trace stays sampled after its error, later spans of the trace are sampled within this time.
+	erroredTraceTTL = time.Minute
+	// erroredTraceMaxCount is max number of remembered errored traces, expired ones are pruned when exceeded.
+	erroredTraceMaxCount = 10000
+)
+
+// TracerOption customizes Tracer beyond TracerConfig (used by WithTracer()).
+type TracerOption func(opts *tracerOptions)
+
+// tracerOptions holds settings of Tracer customized by TracerOption.
+type tracerOptions struct {
+	errorSampling bool
+}
+
+// WithErrorSampling exports spans of errored requests even if their trace is not sampled by SampleRatio.
+// Unsampled spans are recorded (but not exported) instead of dropped, when a span fails (SetError, RecordError or error status)
+// its trace is remembered for a while, then the span and later spans of the trace are exported.
+//
+// This is best-effort: only the errored span and spans of the trace ending after the error are exported,
+// spans ended before the error (e.g. earlier siblings) and spans in other services are lost.
+// Recording unsampled spans costs some CPU and memory. It has no effect if all traces are sampled.
+//
+// Example:
+//
+//	otel.WithTracer(&otel.TracerConfig{..., SampleRatio: 0.05}, otel.WithErrorSampling())
+func WithErrorSampling() TracerOption {
+	return func(opts *tracerOptions) {
+		opts.errorSampling = true
+	}
+}
+
+// erroredTraces is set of remembered errored traces (nil if error sampling is disabled),
+// it is package-level since Span has no reference to its Tracer.
+var erroredTraces atomic.Pointer[erroredTraceSet]
+
+// erroredTraceSet remembers IDs of traces having an errored span with time they were marked.
+type erroredTraceSet struct {
+	mu     sync.Mutex
+	traces map[trace.TraceID]time.Time
+}
+
+func newErroredTraceSet() *erroredTraceSet {
+	return &erroredTraceSet{
+		traces: make(map[trace.TraceID]time.Time),
+	}
+}
+
+// mark remembers trace as errored.
+func (set *erroredTraceSet) mark(traceID trace.TraceID) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	now := time.Now()
+	if len(set.traces) >= erroredTraceMaxCount {
+		for id, markedAt := range set.traces {
+			if now.Sub(markedAt) > erroredTraceTTL {
+				delete(set.traces, id)
+			}
+		}
+	}
+	// Still full of live traces, drop the new one rather than growing without bound
+	if len(set.traces) >= erroredTraceMaxCount {
+		return
+	}
+	set.traces[traceID] = now
+}
+
+// contains reports whether trace is marked as errored and not expired.
+func (set *erroredTraceSet) contains(traceID trace.TraceID) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+
+	markedAt, ok := set.traces[traceID]
+	return ok && time.Since(markedAt) <= erroredTraceTTL
+}
+
+// markErroredSpan marks trace of the span as errored if error sampling is enabled.
+func markErroredSpan(span trace.Span) {
+	set := erroredTraces.Load()
+	if set == nil {
+		return
+	}
+
+	if spanCtx := span.SpanContext(); spanCtx.HasTraceID() {
+		set.mark(spanCtx.TraceID())
+	}
+}
+
+// errorSampler samples spans of errored traces, and records (instead of dropping) spans not sampled by base sampler,
+// so they can still be exported by errorSamplingProcessor if they fail.
+type errorSampler struct {
+	base sdktrace.Sampler
+	set  *erroredTraceSet
+}
+
+func (sampler *errorSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler.set.contains(params.TraceID) {
+		return sdktrace.SamplingResult{
+			Decision:   sdktrace.RecordAndSample,
+			Tracestate: trace.SpanContextFromContext(params.ParentContext).TraceState(),
+		}
+	}
+
+	result := sampler.base.ShouldSample(params)
+	if result.Decision == sdktrace.Drop {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (sampler *errorSampler) Description() string {
+	return "ErrorSampler{" + sampler.base.Description() + "}"
+}
+
+// errorSamplingProcessor forwards sampled spans to next processor, unsampled spans are forwarded (as sampled)
+// only if they failed or their trace is errored, other unsampled spans are dropped.
+type errorSamplingProcessor struct {
+	next sdktrace.SpanProcessor
+	set  *erroredTraceSet
+}
+
+func (processor *errorSamplingProcessor) OnStart(parent context.Context, span sdktrace.ReadWriteSpan) {
+	processor.next.OnStart(parent, span)
+}
+
+func (processor *errorSamplingProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	spanCtx := span.SpanContext()
+	if spanCtx.IsSampled() {
+		processor.next.OnEnd(span)
+		return
+	}
+
+	if span.Status().Code == codes.Error {
+		processor.set.mark(spanCtx.TraceID())
+	} else if !processor.set.contains(spanCtx.TraceID()) {
+		return
+	}
+	processor.next.OnEnd(&sampledSpan{ReadOnlySpan: span})
+}
+
+func (processor *errorSamplingProcessor) Shutdown(ctx context.Context) error {
+	return processor.next.Shutdown(ctx)
+}
+
+func (processor *errorSamplingProcessor) ForceFlush(ctx context.Context) error {
+	return processor.next.ForceFlush(ctx)
+}
+
+// sampledSpan is a recorded-only span upgraded to sampled, so batch processor exports it.
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (span *sampledSpan) SpanContext() trace.SpanContext {
+	spanCtx := span.ReadOnlySpan.SpanContext()
+	return spanCtx.WithTraceFlags(spanCtx.TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,88 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// grpcMetadataCarrier adapts gRPC metadata to TextMapCarrier for trace propagation.
+type grpcMetadataCarrier metadata.MD
+
+// Get returns the first value of key, empty string if not found.
+func (carrier grpcMetadataCarrier) Get(key string) string {
+	values := metadata.MD(carrier).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// Set overwrites values of key.
+func (carrier grpcMetadataCarrier) Set(key string, value string) {
+	metadata.MD(carrier).Set(key, value)
+}
+
+// Keys returns all keys of metadata.
+func (carrier grpcMetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// InjectIntoGRPCMetadata returns a copy of context with trace context of the span in ctx injected into outgoing gRPC metadata.
+// Existing outgoing metadata is kept.
+//
+// Example:
+//
+//	ctx = otel.InjectIntoGRPCMetadata(ctx)
+//	resp, err := client.GetExample(ctx, req)
+func InjectIntoGRPCMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, grpcMetadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// ExtractFromGRPCMetadata returns a copy of context continuing the trace propagated in incoming gRPC metadata.
+// Returns ctx unchanged if there is no incoming metadata.
+//
+// Example:
+//
+//	func (s *server) GetExample(ctx context.Context, req *pb.GetExampleRequest) (*pb.Example, error) {
+//	    ctx = otel.ExtractFromGRPCMetadata(ctx)
+//	    ctx, span := observer.NewSpan(ctx, "GetExample")
+//	    defer span.Done()
+//	    ...
+//	}
+func ExtractFromGRPCMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, grpcMetadataCarrier(md))
+}
+
+// GRPCUnaryClientInterceptor returns gRPC unary client interceptor propagating trace context of every call.
+//
+// Example:
+//
+//	conn, err := grpc.NewClient("service-b:9090",
+//	    grpc.WithTransportCredentials(insecure.NewCredentials()),
+//	    grpc.WithUnaryInterceptor(otel.GRPCUnaryClientInterceptor()),
+//	)
+func GRPCUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(InjectIntoGRPCMetadata(ctx), method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,61 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
+// Timeout of each connectivity check when ctx has no deadline.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheck checks connectivity to Redis of Cache (if configured) and OTLP endpoints of Tracer, Logger and Meter.
+// OTLP endpoints are checked by opening a TCP connection, no telemetry data is sent.
+// Returns nil if all backends are reachable, otherwise an error joining all failures.
+//
+// Example:
+//
+//	r.GET("/readyz", func(c *gin.Context) {
+//	    if err := observer.HealthCheck(c.Request.Context()); err != nil {
+//	        c.String(http.StatusServiceUnavailable, err.Error())
+//	        return
+//	    }
+//	    c.Status(http.StatusOK)
+//	})
+func (o *Observer) HealthCheck(ctx context.Context) error {
+	errs := make([]error, 0)
+
+	if o.cache != nil {
+		pingCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		if err := o.cache.ping(pingCtx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to ping Redis of Cache: %v", err))
+		}
+		cancel()
+	}
+
+	endPoints := slices.Clone(o.otlpEndPoints)
+	if o.meterConfig != nil {
+		endPoints = append(endPoints, o.meterConfig.EndPoint)
+	}
+	slices.Sort(endPoints)
+	for _, endPoint := range slices.Compact(endPoints) {
+		if err := checkOTLPEndPoint(ctx, endPoint); err != nil {
+			errs = append(errs, fmt.Errorf("failed to connect to OTLP endpoint '%s': %v", endPoint, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkOTLPEndPoint opens and closes a TCP connection to endPoint (host:port).
+func checkOTLPEndPoint(ctx context.Context, endPoint string) error {
+	dialer := net.Dialer{Timeout: healthCheckTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", endPoint)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
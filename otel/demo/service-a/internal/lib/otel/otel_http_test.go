@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecoveryMiddlewareRecordsPanicOnRequestSpan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tracerProvider.Shutdown(context.Background())
+
+	engine := gin.New()
+	// Stand-in for HTTPMiddleware, starts the request span
+	engine.Use(func(c *gin.Context) {
+		ctx, span := tracerProvider.Tracer("test").Start(c.Request.Context(), "GET /panic")
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	engine.Use(RecoveryMiddleware(NewNoopObserver(), nil))
+	engine.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, expected 500", w.Code)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected only the request span, got %d spans", len(spans))
+	}
+	span := spans[0]
+	if span.Status().Code != codes.Error || !strings.Contains(span.Status().Description, "boom") {
+		t.Errorf("span status = %v %q, expected Error with panic value", span.Status().Code, span.Status().Description)
+	}
+
+	var stack string
+	for _, event := range span.Events() {
+		if event.Name != "exception" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if attr.Key == "exception.stacktrace" {
+				stack = attr.Value.AsString()
+			}
+		}
+	}
+	if !strings.Contains(stack, "TestRecoveryMiddlewareRecordsPanicOnRequestSpan") {
+		t.Errorf("exception event has no stack of the panicking handler: %q", stack)
+	}
+}
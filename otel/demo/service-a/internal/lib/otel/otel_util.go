@@ -2,18 +2,45 @@ package otel
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
 	"math"
 	"net"
 	"os"
+	"sort"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
 // stdLog is used for internal logging
 var stdLog = log.New(os.Stdout, "[otel] ", log.LstdFlags)
 
+// ExportProtocol defines the OTLP protocol used for exporting telemetry data.
+type ExportProtocol string
+
+// Export protocol definitions for Tracer, Meter and Logger.
+const (
+	// EXPORT_PROTOCOL_HTTP is used for exporting via OTLP/HTTP (default, port 4318).
+	EXPORT_PROTOCOL_HTTP ExportProtocol = "http"
+	// EXPORT_PROTOCOL_GRPC is used for exporting via OTLP/gRPC (port 4317).
+	EXPORT_PROTOCOL_GRPC ExportProtocol = "grpc"
+)
+
+// validate checks the export protocol is supported, empty protocol is EXPORT_PROTOCOL_HTTP.
+func (protocol ExportProtocol) validate() error {
+	switch protocol {
+	case "", EXPORT_PROTOCOL_HTTP, EXPORT_PROTOCOL_GRPC:
+		return nil
+	default:
+		return fmt.Errorf("export protocol '%s' is not valid", protocol)
+	}
+}
+
 // mapToAttribute converts a map to OpenTelemetry attributes.
 // Supports common Go types: string, bool, int, int64, uint, uint64, float32, float64
 // and their slice variants. Unsupported types are logged and skipped.
@@ -110,6 +137,21 @@ func mapToAttribute(attrMap map[string]any) []attribute.KeyValue {
 	return attrs
 }
 
+// mapToLogAttr converts a map to slog attributes sorted by key.
+// Supports the same types as mapToAttribute, unsupported types are logged and skipped.
+func mapToLogAttr(attrMap map[string]any) []slog.Attr {
+	kvs := mapToAttribute(attrMap)
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+
+	attrs := make([]slog.Attr, 0, len(kvs))
+	for _, kv := range kvs {
+		attrs = append(attrs, slog.Any(string(kv.Key), kv.Value.AsInterface()))
+	}
+	return attrs
+}
+
 // getTraceInfo extracts trace_id and span_id from context.
 // Returns empty strings if context has no active span.
 func getTraceInfo(ctx context.Context) (string, string) {
@@ -160,3 +202,34 @@ func getLocalIP() string {
 
 	return ""
 }
+
+// newResource returns resource with service metadata and host IP, merged with custom resource attributes.
+// Custom attributes override the built-in ones of the same key.
+func newResource(serviceName string, serviceVersion string, resourceAttrs map[string]string) *resource.Resource {
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(serviceVersion),
+		attribute.String("host.ip", getLocalIP()),
+	}
+
+	keys := make([]string, 0, len(resourceAttrs))
+	for key := range resourceAttrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		attrs = append(attrs, attribute.String(key, resourceAttrs[key]))
+	}
+
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...)
+}
+
+// validateResourceAttrs checks keys of custom resource attributes.
+func validateResourceAttrs(resourceAttrs map[string]string) error {
+	for key := range resourceAttrs {
+		if key == "" {
+			return errors.New("resource attribute key must not be empty")
+		}
+	}
+	return nil
+}
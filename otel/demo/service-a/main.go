@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"thanhldt060802/common/apperror"
 	"thanhldt060802/common/constant"
 	"thanhldt060802/common/pubsub"
 	"thanhldt060802/internal"
@@ -54,9 +55,12 @@ func init() {
 		Database: viper.GetInt("redis.database"),
 		Password: viper.GetString("redis.password"),
 	})
-	pubsub.RedisPubInstance = pubsub.NewRedisPub[*model.ExamplePubSubMessage](redisclient.RedisClientConnInstance.GetClient())
+	pubsub.RedisPubInstance = pubsub.NewRedisPub[*model.ExamplePubSubMessage](
+		redisclient.RedisClientConnInstance.GetClient(),
+		pubsub.WithPublishRetry(3, 100*time.Millisecond, 2*time.Second),
+	)
 
-	internal.Observer = otel.NewOtelObserver(
+	internal.Observer = otel.MustNewOtelObserver(
 		otel.WithTracer(&otel.TracerConfig{
 			ServiceName:    viper.GetString("app.name"),
 			ServiceVersion: viper.GetString("app.version"),
@@ -65,6 +69,7 @@ func init() {
 			HttpHeader: map[string]string{
 				"Authorization": "Bearer " + viper.GetString("observer.tracer.bearer_token"),
 			},
+			SampleRatio: viper.GetFloat64("observer.tracer.sample_ratio"),
 		}),
 		otel.WithLogger(&otel.LoggerConfig{
 			ServiceName:    viper.GetString("app.name"),
@@ -74,8 +79,9 @@ func init() {
 			HttpHeader: map[string]string{
 				"Authorization": "Bearer " + viper.GetString("observer.logger.bearer_token"),
 			},
-			LocalLogFile:  viper.GetString("observer.logger.local_log_file"),
-			LocalLogLevel: otel.LogLevel(viper.GetString("observer.logger.local_log_level")),
+			LocalLogFile:   viper.GetString("observer.logger.local_log_file"),
+			LocalLogLevel:  otel.LogLevel(viper.GetString("observer.logger.local_log_level")),
+			LocalLogFormat: otel.LogFormat(viper.GetString("observer.logger.local_log_format")),
 		}),
 		otel.WithMeter(&otel.MeterConfig{
 			ServiceName:    viper.GetString("app.name"),
@@ -117,6 +123,7 @@ func init() {
 			Address:         viper.GetString("observer.cache_redis.address"),
 			Database:        viper.GetInt("observer.cache_redis.database"),
 			Password:        viper.GetString("observer.cache_redis.password"),
+			Channel:         viper.GetString("observer.cache_redis.channel"),
 			PoolSize:        20,
 			PoolTimeoutSec:  20,
 			IdleTimeoutSec:  10,
@@ -177,6 +184,7 @@ func main() {
 	})
 
 	humaAPI := humagin.New(router, humaConfig)
+	apperror.RegisterOpenAPIErrors(humaAPI)
 	api := hureg.NewAPIGen(humaAPI)
 	api = api.AddBasePath(fmt.Sprintf("%v/%v", server.APP_NAME, server.APP_VERSION[:2]))
 
@@ -185,6 +193,7 @@ func main() {
 	initRepository()
 
 	apiV1.RegisterAPIExample(api, service.NewExampleService())
+	apiV1.RegisterAPIDiagnostics(api, internal.Observer)
 
 	startGaugeCollector()
 
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"thanhldt060802/internal"
 	"thanhldt060802/internal/sqlclient"
 	"thanhldt060802/model"
 	"thanhldt060802/repository"
@@ -15,13 +14,16 @@ import (
 )
 
 type ExampleRepo struct {
+	*BaseRepo[model.Example]
 }
 
 func NewExampleRepo() repository.IExampleRepo {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 	defer cancel()
 
-	repo := &ExampleRepo{}
+	repo := &ExampleRepo{
+		BaseRepo: NewBaseRepo[model.Example]("Example"),
+	}
 	repo.DeleteTable(ctx)
 	repo.InitTable(ctx)
 	repo.GenerateData(ctx)
@@ -30,13 +32,13 @@ func NewExampleRepo() repository.IExampleRepo {
 }
 
 func (repo *ExampleRepo) DeleteTable(ctx context.Context) {
-	if err := repository.DropTable(sqlclient.SqlClientConnInstance, ctx, (*model.Example)(nil)); err != nil {
+	if err := repo.DropTable(ctx); err != nil {
 		panic(err)
 	}
 }
 
 func (repo *ExampleRepo) InitTable(ctx context.Context) {
-	if err := repository.CreateTable(sqlclient.SqlClientConnInstance, ctx, (*model.Example)(nil)); err != nil {
+	if err := repo.CreateTable(ctx); err != nil {
 		panic(err)
 	}
 }
@@ -60,28 +62,5 @@ func (repo *ExampleRepo) GenerateData(ctx context.Context) {
 }
 
 func (repo *ExampleRepo) GetById(ctx context.Context, exampleUuid string) (*model.Example, error) {
-	ctx, span := internal.Observer.NewSpan(ctx, "GetExampleById-Repository")
-	defer span.Done()
-
-	internal.Observer.InfoLogWithCtx(ctx, "[Repository layer] Get Example by example_uuid='%s'", exampleUuid)
-
-	example := new(model.Example)
-
-	query := sqlclient.SqlClientConnInstance.GetDB().NewSelect().Model(example).
-		Where("example_uuid = ?", exampleUuid)
-
-	span.AddEvent("Execute SQL", map[string]any{
-		"sql": query.String(),
-	})
-
-	err := query.Scan(ctx)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	} else if err != nil {
-		internal.Observer.ErrorLogWithCtx(ctx, "[Repository layer] Failed to get Example by example_uuid='%s'", exampleUuid)
-		span.SetError(err)
-		return nil, err
-	} else {
-		return example, nil
-	}
+	return repo.BaseRepo.GetById(ctx, "example_uuid", exampleUuid)
 }
@@ -3,6 +3,9 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"thanhldt060802/common/apperror"
+	"thanhldt060802/common/constant"
+	"thanhldt060802/internal"
 	"thanhldt060802/internal/lib/otel"
 	"time"
 
@@ -18,7 +21,11 @@ var (
 
 func NewHTTPServer() *gin.Engine {
 	engine := gin.New()
+	engine.Use(otel.ClientIPMiddleware())
 	engine.Use(otel.GinMiddlewares(APP_NAME)...)
+	engine.Use(otel.RecoveryMiddleware(internal.Observer, func(c *gin.Context, _ error) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, apperror.ErrInternalServerError(nil, "Internal server error", string(constant.ERR_INTERNAL_SERVER_ERROR)))
+	}))
 	engine.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"service-name": APP_NAME,
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"thanhldt060802/common/response"
+	"thanhldt060802/internal/lib/otel"
+
+	authMdw "thanhldt060802/middleware/auth"
+
+	"github.com/cardinalby/hureg"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+type apiDiagnostics struct {
+	observer otel.IObserver
+}
+
+// RegisterAPIDiagnostics mounts GET /internal/stats returning Observer internals (see otel.Observer.DiagnosticsSnapshot).
+func RegisterAPIDiagnostics(api hureg.APIGen, observer otel.IObserver) {
+	handler := &apiDiagnostics{
+		observer: observer,
+	}
+
+	apiGroup := api.AddBasePath("/internal")
+
+	hureg.Register(
+		apiGroup,
+		huma.Operation{
+			OperationID: "get-internal-stats",
+			Method:      http.MethodGet,
+			Path:        "/stats",
+			Security:    authMdw.DefaultAuthSecurity,
+			Description: "Get internal stats (cache of trace carriers, gauge values).",
+			Middlewares: huma.Middlewares{authMdw.NewAuthMiddleware(api)},
+		},
+		handler.GetStats,
+	)
+}
+
+func (handler *apiDiagnostics) GetStats(ctx context.Context, req *struct{}) (res *response.GenericResponse[otel.Diagnostics], err error) {
+	res = response.Ok(handler.observer.DiagnosticsSnapshot(ctx))
+	return
+}
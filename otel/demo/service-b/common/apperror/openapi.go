@@ -0,0 +1,85 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"thanhldt060802/common/constant"
+
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// knownErrorCodes is the list of constant error codes documented in OpenAPI.
+var knownErrorCodes = []constant.ERROR_CODE{
+	constant.ERR_BAD_REQUEST,
+	constant.ERR_UNAUTHORIZED,
+	constant.ERR_FORBIDDEN,
+	constant.ERR_SERVICE_UNAVAILABLE,
+	constant.ERR_INTERNAL_SERVER_ERROR,
+}
+
+// errorExamples returns a representative error body of each documented status.
+func errorExamples() []*CustomError {
+	return []*CustomError{
+		ErrBadRequest("Invalid request body", "body.example_uuid: expected string"),
+		ErrUnauthorized(nil, "Invalid token"),
+		ErrForbidden(nil, "Permission denied"),
+		ErrNotFound("Example example_uuid='00000000-0000-0000-0000-000000000000' not found", "ERR_EXAMPLE_NOT_FOUND"),
+		ErrInternalServerError(errors.New("connection refused"), "Internal server error", string(constant.ERR_INTERNAL_SERVER_ERROR)),
+		ErrServiceUnavailable(nil, "Failed to get example").(*CustomError),
+	}
+}
+
+// RegisterOpenAPIErrors documents CustomError in OpenAPI components of api: the CustomError schema with known error codes,
+// an example of each status (e.g. "ErrBadRequest") and a reusable response of each status with the same name.
+// Call it during API setup, before serving OpenAPI.
+//
+// Example:
+//
+//	humaAPI := humagin.New(router, humaConfig)
+//	apperror.RegisterOpenAPIErrors(humaAPI)
+func RegisterOpenAPIErrors(api huma.API) {
+	components := api.OpenAPI().Components
+
+	schemaRef := components.Schemas.Schema(reflect.TypeOf(CustomError{}), true, "CustomError")
+	if schema := components.Schemas.SchemaFromRef(schemaRef.Ref); schema != nil {
+		codes := make([]string, len(knownErrorCodes))
+		for i, code := range knownErrorCodes {
+			codes[i] = string(code)
+		}
+		schema.Description = "Error returned by all APIs"
+		if codeSchema, ok := schema.Properties["code"]; ok {
+			codeSchema.Description = "Error code, one of known codes (" + strings.Join(codes, ", ") + ") or a specific code (e.g. ERR_EXAMPLE_NOT_FOUND)"
+		}
+		if detailsSchema, ok := schema.Properties["details"]; ok {
+			detailsSchema.Description = "Details of error (e.g. invalid fields, causes)"
+		}
+	}
+
+	if components.Examples == nil {
+		components.Examples = map[string]*huma.Example{}
+	}
+	if components.Responses == nil {
+		components.Responses = map[string]*huma.Response{}
+	}
+	for _, example := range errorExamples() {
+		exampleName := "Err" + strings.ReplaceAll(http.StatusText(example.Status), " ", "")
+		components.Examples[exampleName] = &huma.Example{
+			Summary: http.StatusText(example.Status),
+			Value:   example,
+		}
+
+		components.Responses[exampleName] = &huma.Response{
+			Description: http.StatusText(example.Status),
+			Content: map[string]*huma.MediaType{
+				"application/json": {
+					Schema: schemaRef,
+					Examples: map[string]*huma.Example{
+						exampleName: {Ref: "#/components/examples/" + exampleName},
+					},
+				},
+			},
+		}
+	}
+}
@@ -3,7 +3,13 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"thanhldt060802/internal"
 	"thanhldt060802/model"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
@@ -13,15 +19,71 @@ var RedisPubInstance IRedisPub[*model.ExamplePubSubMessage]
 
 type IRedisPub[T any] interface {
 	Publish(ctx context.Context, channel string, data T) error
+	PublishBatch(ctx context.Context, channel string, dataList []T) error
+}
+
+// ITraceCarrierMessage is implemented by messages carrying a Trace Carrier.
+// InjectTraceCarrier should only set Trace Carrier from ctx when the message does not carry one.
+// It modifies the message in place, so it should be implemented on a pointer receiver.
+type ITraceCarrierMessage interface {
+	InjectTraceCarrier(ctx context.Context)
+}
+
+// PublishBatchError contains errors of failed messages by their indices in the batch.
+type PublishBatchError struct {
+	Errors map[int]error
+}
+
+func (e *PublishBatchError) Error() string {
+	indices := make([]int, 0, len(e.Errors))
+	for index := range e.Errors {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	msgs := make([]string, len(indices))
+	for i, index := range indices {
+		msgs[i] = fmt.Sprintf("[%d]: %v", index, e.Errors[index])
+	}
+	return fmt.Sprintf("publish %d message(s) failed: %s", len(indices), strings.Join(msgs, "; "))
 }
 
 type RedisPub[T any] struct {
 	client *redis.Client
+	retry  publishRetry
 }
 
-func NewRedisPub[T any](client *redis.Client) IRedisPub[T] {
+// publishRetry configures retry of Publish, Publish is not retried with a single attempt.
+type publishRetry struct {
+	attempts  int
+	baseDelay time.Duration
+	maxDelay  time.Duration
+}
+
+// RedisPubOption customizes Redis Pub.
+type RedisPubOption func(retry *publishRetry)
+
+// WithPublishRetry retries failed Publish up to attempts times in total, delay between attempts grows
+// exponentially from baseDelay and is capped by maxDelay (jittered).
+func WithPublishRetry(attempts int, baseDelay time.Duration, maxDelay time.Duration) RedisPubOption {
+	return func(retry *publishRetry) {
+		retry.attempts = max(attempts, 1)
+		retry.baseDelay = baseDelay
+		retry.maxDelay = max(maxDelay, baseDelay)
+	}
+}
+
+func NewRedisPub[T any](client *redis.Client, opts ...RedisPubOption) IRedisPub[T] {
+	retry := publishRetry{
+		attempts: 1,
+	}
+	for _, opt := range opts {
+		opt(&retry)
+	}
+
 	return &RedisPub[T]{
 		client: client,
+		retry:  retry,
 	}
 }
 
@@ -31,7 +93,7 @@ func (redisPub *RedisPub[T]) Publish(ctx context.Context, channel string, data T
 		log.Errorf("Marshal data failed: %v", err.Error())
 		return err
 	}
-	if err := redisPub.client.Publish(ctx, channel, payload).Err(); err != nil {
+	if err := redisPub.publishWithRetry(ctx, channel, payload); err != nil {
 		log.Errorf("Publish %v to %v failed: %v", data, channel, err.Error())
 		return err
 	}
@@ -39,3 +101,81 @@ func (redisPub *RedisPub[T]) Publish(ctx context.Context, channel string, data T
 	log.Errorf("Publish %v to %v successful", data, channel)
 	return nil
 }
+
+// PublishBatch publishes all messages to channel in a single Redis pipeline.
+// Messages carrying a Trace Carrier keep their own one, empty one is injected from ctx.
+// Injection modifies messages of dataList in place (no copy is made), so after PublishBatch
+// each message carries the Trace Carrier it was published with.
+// Returns *PublishBatchError identifying failed messages if any.
+func (redisPub *RedisPub[T]) PublishBatch(ctx context.Context, channel string, dataList []T) error {
+	batchErr := &PublishBatchError{
+		Errors: make(map[int]error),
+	}
+
+	pipe := redisPub.client.Pipeline()
+	cmds := make(map[int]*redis.IntCmd, len(dataList))
+	for i, data := range dataList {
+		if message, ok := any(data).(ITraceCarrierMessage); ok {
+			message.InjectTraceCarrier(ctx)
+		}
+
+		payload, err := json.Marshal(data)
+		if err != nil {
+			log.Errorf("Marshal data failed: %v", err.Error())
+			batchErr.Errors[i] = err
+			continue
+		}
+		cmds[i] = pipe.Publish(ctx, channel, payload)
+	}
+
+	if len(cmds) > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Errorf("Publish batch to %v failed: %v", channel, err.Error())
+		}
+		for i, cmd := range cmds {
+			if err := cmd.Err(); err != nil {
+				batchErr.Errors[i] = err
+			}
+		}
+	}
+
+	if len(batchErr.Errors) > 0 {
+		return batchErr
+	}
+
+	log.Infof("Publish batch of %v message(s) to %v successful", len(dataList), channel)
+	return nil
+}
+
+// publishWithRetry publishes payload, retrying with backoff until attempts are exhausted or ctx is done.
+func (redisPub *RedisPub[T]) publishWithRetry(ctx context.Context, channel string, payload []byte) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = redisPub.client.Publish(ctx, channel, payload).Err(); err == nil {
+			return nil
+		}
+		if attempt >= redisPub.retry.attempts {
+			return err
+		}
+
+		delay := redisPub.retry.delay(attempt)
+		internal.Observer.WarnLogWithCtx(ctx, "[Redis Pub] Publish to channel '%s' failed: %v, retrying attempt %d in %v", channel, err, attempt, delay)
+
+		select {
+		case <-ctx.Done():
+			{
+				return ctx.Err()
+			}
+		case <-time.After(delay):
+		}
+	}
+}
+
+// delay returns exponential backoff delay after the attempt, capped by maxDelay and jittered.
+func (retry publishRetry) delay(attempt int) time.Duration {
+	delay := retry.maxDelay
+	if attempt < 16 {
+		delay = min(retry.baseDelay<<(attempt-1), retry.maxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -7,6 +7,8 @@ type PaginationBodyResponse[T any] struct {
 	Total   int    `json:"total" required:"false"`
 	Offset  int    `json:"offset" required:"false"`
 	Limit   int    `json:"limit" required:"false"`
+
+	Estimated bool `json:"estimated,omitempty" required:"false" doc:"Total is an estimation (e.g. from table statistics), not an exact count"`
 }
 
 type PaginationResponse[T any] struct {
@@ -69,3 +71,11 @@ func Pagination[T any](data T, total int, offset int, limit int, msgs ...string)
 	}
 	return
 }
+
+// PaginationEstimated is like Pagination but total is an estimation (e.g. from pg_class.reltuples) instead of an exact COUNT(*),
+// response is flagged with estimated=true so clients don't rely on total for exact paging.
+func PaginationEstimated[T any](data T, estimatedTotal int, offset int, limit int, msgs ...string) (res *PaginationResponse[T]) {
+	res = Pagination(data, estimatedTotal, offset, limit, msgs...)
+	res.Body.Estimated = true
+	return
+}
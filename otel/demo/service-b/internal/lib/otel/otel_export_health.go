@@ -0,0 +1,152 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Metric names of telemetry pipeline health, registered on Meter automatically.
+const (
+	// exportErrorsMetricName counts errors reported to OpenTelemetry error handler (failed exports and SDK internal errors).
+	exportErrorsMetricName MetricName = "otel_export_errors_total"
+	// exportHealthyMetricName is 1 if the last export of signal succeeded, 0 if it failed or no export happened yet.
+	exportHealthyMetricName MetricName = "otel_export_healthy"
+	// droppedAttrSetsMetricName counts metric records dropped by MaxAttrCardinality, per metric.
+	droppedAttrSetsMetricName MetricName = "otel_metric_attr_sets_dropped_total"
+)
+
+// Signals whose export health is tracked, used as value of attribute signal of health metrics.
+const (
+	exportSignalTraces  = "traces"
+	exportSignalMetrics = "metrics"
+	exportSignalLogs    = "logs"
+)
+
+// Results of the last export of a signal.
+const (
+	exportResultUnknown int32 = iota // No export happened yet
+	exportResultSucceeded
+	exportResultFailed
+)
+
+// exportHealth tracks health of telemetry pipeline of an Observer.
+// Errors are counted by OpenTelemetry error handler installed by NewOtelObserver,
+// results of exports are reported by exporter wrappers of configured signals.
+type exportHealth struct {
+	errors  atomic.Int64                   // Number of errors reported to OpenTelemetry error handler
+	signals map[string]*signalExportHealth // Configured signals, only filled while options are applied
+}
+
+// signalExportHealth tracks result of the last export of a signal.
+type signalExportHealth struct {
+	lastExport atomic.Int32 // One of exportResult*
+}
+
+func newExportHealth() *exportHealth {
+	return &exportHealth{
+		signals: make(map[string]*signalExportHealth),
+	}
+}
+
+// track starts tracking the given signal, its health is reported to the returned signalExportHealth by exporter wrapper.
+func (h *exportHealth) track(signal string) *signalExportHealth {
+	health := &signalExportHealth{}
+	h.signals[signal] = health
+	return health
+}
+
+// recordError is called from OpenTelemetry error handler.
+func (h *exportHealth) recordError() {
+	h.errors.Add(1)
+}
+
+// record is called by exporter wrapper with the result of an export.
+func (h *signalExportHealth) record(err error) {
+	if err != nil {
+		h.lastExport.Store(exportResultFailed)
+		return
+	}
+	h.lastExport.Store(exportResultSucceeded)
+}
+
+// registerMetrics registers export error counter and export health gauge on meter,
+// the gauge has attribute signal per tracked signal, so signals which are not configured are not reported.
+func (h *exportHealth) registerMetrics(meter metric.Meter) error {
+	errorsCounter, err := meter.Int64ObservableCounter(
+		exportErrorsMetricName.Get().String(),
+		metric.WithDescription("Number of errors reported by OpenTelemetry (failed exports and SDK internal errors)"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metric '%s': %v", exportErrorsMetricName, err)
+	}
+
+	healthyGauge, err := meter.Int64ObservableGauge(
+		exportHealthyMetricName.Get().String(),
+		metric.WithDescription("Whether the last OpenTelemetry export of signal succeeded (1), or failed or has not happened yet (0)"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metric '%s': %v", exportHealthyMetricName, err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		observer.ObserveInt64(errorsCounter, h.errors.Load())
+
+		for signal, health := range h.signals {
+			healthy := int64(0)
+			if health.lastExport.Load() == exportResultSucceeded {
+				healthy = 1
+			}
+			observer.ObserveInt64(healthyGauge, healthy, metric.WithAttributes(attribute.String("signal", signal)))
+		}
+		return nil
+	}, errorsCounter, healthyGauge)
+	if err != nil {
+		return fmt.Errorf("failed to register callback of export health metrics: %v", err)
+	}
+
+	return nil
+}
+
+// exportHealthSpanExporter reports results of span exports to health.
+type exportHealthSpanExporter struct {
+	sdktrace.SpanExporter
+	health *signalExportHealth
+}
+
+func (e *exportHealthSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.health.record(err)
+	return err
+}
+
+// exportHealthMetricExporter reports results of metric exports to health.
+type exportHealthMetricExporter struct {
+	sdkmetric.Exporter
+	health *signalExportHealth
+}
+
+func (e *exportHealthMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	e.health.record(err)
+	return err
+}
+
+// exportHealthLogExporter reports results of log exports to health.
+type exportHealthLogExporter struct {
+	log.Exporter
+	health *signalExportHealth
+}
+
+func (e *exportHealthLogExporter) Export(ctx context.Context, records []log.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	e.health.record(err)
+	return err
+}
@@ -0,0 +1,126 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// fakeSpanExporter returns err from every export.
+type fakeSpanExporter struct {
+	err error
+}
+
+func (e *fakeSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return e.err
+}
+
+func (e *fakeSpanExporter) Shutdown(ctx context.Context) error { return nil }
+
+// fakeMetricExporter returns err from every export.
+type fakeMetricExporter struct {
+	sdkmetric.Exporter
+	err error
+}
+
+func (e *fakeMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return e.err
+}
+
+// collectExportHealth returns export errors and export health per signal from health metrics.
+func collectExportHealth(t *testing.T, reader *sdkmetric.ManualReader) (int64, map[string]int64) {
+	t.Helper()
+
+	errorsSum, ok := collectMetric(t, reader, exportErrorsMetricName).Data.(metricdata.Sum[int64])
+	if !ok || len(errorsSum.DataPoints) != 1 {
+		t.Fatalf("unexpected data of metric '%s'", exportErrorsMetricName)
+	}
+	healthyGauge, ok := collectMetric(t, reader, exportHealthyMetricName).Data.(metricdata.Gauge[int64])
+	if !ok {
+		t.Fatalf("unexpected data of metric '%s'", exportHealthyMetricName)
+	}
+
+	healthy := make(map[string]int64)
+	for _, dataPoint := range healthyGauge.DataPoints {
+		signal, _ := dataPoint.Attributes.Value("signal")
+		healthy[signal.AsString()] = dataPoint.Value
+	}
+	return errorsSum.DataPoints[0].Value, healthy
+}
+
+func TestExportHealthIsTrackedPerConfiguredSignal(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(context.Background())
+
+	// Only traces and metrics are configured
+	health := newExportHealth()
+	spanExporter := &exportHealthSpanExporter{&fakeSpanExporter{}, health.track(exportSignalTraces)}
+	metricExporter := &exportHealthMetricExporter{&fakeMetricExporter{}, health.track(exportSignalMetrics)}
+	if err := health.registerMetrics(meterProvider.Meter("test")); err != nil {
+		t.Fatalf("registerMetrics: %v", err)
+	}
+
+	// No export happened yet, health is unknown
+	_, healthy := collectExportHealth(t, reader)
+	if len(healthy) != 2 || healthy[exportSignalTraces] != 0 || healthy[exportSignalMetrics] != 0 {
+		t.Errorf("healthy before first export = %v, expected traces and metrics only, both 0", healthy)
+	}
+
+	// Traces keep failing while metrics succeed in between, traces must stay unhealthy
+	spanExporter.SpanExporter.(*fakeSpanExporter).err = errors.New("connection refused")
+	spanExporter.ExportSpans(context.Background(), nil)
+	metricExporter.Export(context.Background(), nil)
+	spanExporter.ExportSpans(context.Background(), nil)
+	metricExporter.Export(context.Background(), nil)
+
+	_, healthy = collectExportHealth(t, reader)
+	if healthy[exportSignalTraces] != 0 {
+		t.Errorf("traces healthy = %d, expected 0", healthy[exportSignalTraces])
+	}
+	if healthy[exportSignalMetrics] != 1 {
+		t.Errorf("metrics healthy = %d, expected 1", healthy[exportSignalMetrics])
+	}
+	if _, ok := healthy[exportSignalLogs]; ok {
+		t.Errorf("logs are not configured but reported: %v", healthy)
+	}
+
+	// Recovered traces become healthy again
+	spanExporter.SpanExporter.(*fakeSpanExporter).err = nil
+	spanExporter.ExportSpans(context.Background(), nil)
+	if _, healthy = collectExportHealth(t, reader); healthy[exportSignalTraces] != 1 {
+		t.Errorf("traces healthy = %d after successful export, expected 1", healthy[exportSignalTraces])
+	}
+}
+
+func TestExportErrorsAreCountedByErrorHandler(t *testing.T) {
+	observer, err := NewOtelObserver()
+	if err != nil {
+		t.Fatalf("NewOtelObserver: %v", err)
+	}
+	defer ResetObserver(observer)
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer meterProvider.Shutdown(context.Background())
+	if err := observer.exportHealth.registerMetrics(meterProvider.Meter("test")); err != nil {
+		t.Fatalf("registerMetrics: %v", err)
+	}
+
+	otel.Handle(errors.New("failed to upload metrics"))
+	otel.Handle(errors.New("propagator failed"))
+
+	// No signal is configured, only the error counter is reported
+	errorsSum, ok := collectMetric(t, reader, exportErrorsMetricName).Data.(metricdata.Sum[int64])
+	if !ok || len(errorsSum.DataPoints) != 1 {
+		t.Fatalf("unexpected data of metric '%s'", exportErrorsMetricName)
+	}
+	if errorsSum.DataPoints[0].Value != 2 {
+		t.Errorf("export errors = %d, expected 2", errorsSum.DataPoints[0].Value)
+	}
+}
@@ -0,0 +1,284 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisGroupCache stores JSON encoded values of type T in Redis, organized as map[group][key]=T.
+// Each group is a Redis hash with key "{keyPrefix}:{channel}:{group}", so a group can be read, counted or removed as a whole.
+// Cache of Trace Carriers is a RedisGroupCache[storedTraceCarrier], which keeps creation time of every Trace Carrier.
+type RedisGroupCache[T any] struct {
+	redisClient *redis.Client
+	channelKey  string        // Key prefix of all groups, "{keyPrefix}:{channel}"
+	groupTTL    time.Duration // Time to live of group, refreshed on every set (0: no expiration)
+}
+
+// NewRedisGroupCache connects to Redis and returns a RedisGroupCache storing groups under "{keyPrefix}:{config.Channel}".
+// config.CarrierTTLSec is used as TTL of groups.
+//
+// Example:
+//
+//	userCache, err := otel.NewRedisGroupCache[User](&otel.RedisConfig{
+//	    Address: "localhost:6379",
+//	    Channel: "service-a",
+//	}, "APP:USER")
+//	if err != nil {
+//	    ...
+//	}
+//	defer userCache.Close()
+//
+//	err = userCache.Set(ctx, "tenant-1", user.Id, user)
+//	user, found, err := userCache.Get(ctx, "tenant-1", userId)
+func NewRedisGroupCache[T any](config *RedisConfig, keyPrefix string) (*RedisGroupCache[T], error) {
+	if config == nil {
+		return nil, errors.New("invalid Redis config: config is required")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Redis config: %v", err)
+	}
+	if keyPrefix == "" {
+		return nil, errors.New("key prefix is required")
+	}
+	config.setDefaults()
+
+	// Create Redis client
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:            config.Address,
+		Username:        config.Username,
+		Password:        config.Password,
+		DB:              config.Database,
+		PoolSize:        config.PoolSize,
+		PoolTimeout:     time.Duration(config.PoolTimeoutSec) * time.Second,
+		ConnMaxIdleTime: time.Duration(config.IdleTimeoutSec) * time.Second,
+		ReadTimeout:     time.Duration(config.ReadTimeoutSec) * time.Second,
+		WriteTimeout:    time.Duration(config.WriteTimeoutSec) * time.Second,
+	})
+
+	// Ping to Redis for connection checking
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := redisClient.Ping(ctx).Result(); err != nil {
+		redisClient.Close()
+		return nil, fmt.Errorf("failed to ping to Redis: %v", err)
+	}
+
+	return &RedisGroupCache[T]{
+		redisClient: redisClient,
+		channelKey:  keyPrefix + ":" + config.Channel,
+		groupTTL:    time.Duration(config.CarrierTTLSec) * time.Second,
+	}, nil
+}
+
+// getGroupKey constructs the full Redis key for a group
+func (gCache *RedisGroupCache[T]) getGroupKey(group string) string {
+	return gCache.channelKey + ":" + group
+}
+
+// Get retrieves value of key in group, found is false (with zero value) if key or group doesn't exist.
+// Expired group is removed by Redis, so its values are returned as not found.
+func (gCache *RedisGroupCache[T]) Get(ctx context.Context, group string, key string) (T, bool, error) {
+	var value T
+
+	rawValue, err := gCache.redisClient.HGet(ctx, gCache.getGroupKey(group), key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return value, false, nil
+		}
+		return value, false, err
+	}
+
+	if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set stores value of key in group and refreshes TTL of the group if configured.
+func (gCache *RedisGroupCache[T]) Set(ctx context.Context, group string, key string, value T) error {
+	byteValue, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	groupKey := gCache.getGroupKey(group)
+
+	if gCache.groupTTL <= 0 {
+		return gCache.redisClient.HSet(ctx, groupKey, key, string(byteValue)).Err()
+	}
+
+	// Redis hash fields can not expire individually, so TTL is set on the whole group
+	_, err = gCache.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, groupKey, key, string(byteValue))
+		pipe.Expire(ctx, groupKey, gCache.groupTTL)
+		return nil
+	})
+	return err
+}
+
+// Delete removes key from group.
+func (gCache *RedisGroupCache[T]) Delete(ctx context.Context, group string, key string) error {
+	return gCache.redisClient.HDel(ctx, gCache.getGroupKey(group), key).Err()
+}
+
+// DeleteGroup removes an entire group.
+func (gCache *RedisGroupCache[T]) DeleteGroup(ctx context.Context, group string) error {
+	return gCache.redisClient.Del(ctx, gCache.getGroupKey(group)).Err()
+}
+
+// deleteGroupAndNotify removes an entire group and publishes group name on Redis channel.
+// Both commands run in a single transaction pipeline (MULTI/EXEC), so subscribers are never notified before the group is removed.
+func (gCache *RedisGroupCache[T]) deleteGroupAndNotify(ctx context.Context, group string, channel string) error {
+	_, err := gCache.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, gCache.getGroupKey(group))
+		pipe.Publish(ctx, channel, group)
+		return nil
+	})
+	return err
+}
+
+// Clear removes all groups of this cache, groups of other key prefixes or channels are not touched.
+func (gCache *RedisGroupCache[T]) Clear(ctx context.Context) error {
+	var cursor uint64
+	// Match only groups of this channel, separator prevents matching channels sharing the same prefix
+	pattern := gCache.channelKey + ":*"
+	keys := make([]string, 0)
+
+	for {
+		existingKeys, nextCursor, err := gCache.redisClient.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			stdLog.Printf("[error] Failed to scan partten '%s' with cursor '%d': %v", pattern, cursor, err)
+		}
+		keys = append(keys, existingKeys...)
+
+		cursor = nextCursor
+		if cursor == 0 {
+			break
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+	return gCache.redisClient.Del(ctx, keys...).Err()
+}
+
+// DeleteWhere scans all groups of this cache and deletes values matched by match, returns number of deleted values.
+// Values failed to be decoded are skipped.
+func (gCache *RedisGroupCache[T]) DeleteWhere(ctx context.Context, match func(group string, key string, value T) bool) (int, error) {
+	var cursor uint64
+	pattern := gCache.channelKey + ":*"
+	groupKeyPrefix := gCache.channelKey + ":"
+	deleted := 0
+
+	for {
+		groupKeys, nextCursor, err := gCache.redisClient.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return deleted, err
+		}
+
+		for _, groupKey := range groupKeys {
+			rawValues, err := gCache.redisClient.HGetAll(ctx, groupKey).Result()
+			if err != nil {
+				return deleted, err
+			}
+
+			group := strings.TrimPrefix(groupKey, groupKeyPrefix)
+			matchedKeys := make([]string, 0)
+			for key, rawValue := range rawValues {
+				var value T
+				if err := json.Unmarshal([]byte(rawValue), &value); err != nil {
+					continue
+				}
+				if match(group, key, value) {
+					matchedKeys = append(matchedKeys, key)
+				}
+			}
+			if len(matchedKeys) == 0 {
+				continue
+			}
+
+			count, err := gCache.redisClient.HDel(ctx, groupKey, matchedKeys...).Result()
+			if err != nil {
+				return deleted, err
+			}
+			deleted += int(count)
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return deleted, nil
+}
+
+// Keys lists keys of all values in a group.
+// Returns empty slice for non-existent group.
+func (gCache *RedisGroupCache[T]) Keys(ctx context.Context, group string) ([]string, error) {
+	keys, err := gCache.redisClient.HKeys(ctx, gCache.getGroupKey(group)).Result()
+	if err != nil {
+		return []string{}, err
+	}
+	return keys, nil
+}
+
+// Count counts values in a group.
+// Returns zero for non-existent group.
+func (gCache *RedisGroupCache[T]) Count(ctx context.Context, group string) (int64, error) {
+	return gCache.redisClient.HLen(ctx, gCache.getGroupKey(group)).Result()
+}
+
+// CountGroups scans all groups of this cache and counts values of each group.
+func (gCache *RedisGroupCache[T]) CountGroups(ctx context.Context) (map[string]int64, error) {
+	var cursor uint64
+	pattern := gCache.channelKey + ":*"
+	groupKeyPrefix := gCache.channelKey + ":"
+	counts := make(map[string]int64)
+
+	for {
+		groupKeys, nextCursor, err := gCache.redisClient.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return counts, err
+		}
+
+		if len(groupKeys) > 0 {
+			pipe := gCache.redisClient.Pipeline()
+			cmds := make([]*redis.IntCmd, len(groupKeys))
+			for i, groupKey := range groupKeys {
+				cmds[i] = pipe.HLen(ctx, groupKey)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return counts, err
+			}
+			for i, groupKey := range groupKeys {
+				counts[strings.TrimPrefix(groupKey, groupKeyPrefix)] = cmds[i].Val()
+			}
+		}
+
+		cursor = nextCursor
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return counts, nil
+}
+
+// Ping checks connectivity to Redis.
+func (gCache *RedisGroupCache[T]) Ping(ctx context.Context) error {
+	return gCache.redisClient.Ping(ctx).Err()
+}
+
+// Close closes Redis connection pool.
+func (gCache *RedisGroupCache[T]) Close() error {
+	return gCache.redisClient.Close()
+}
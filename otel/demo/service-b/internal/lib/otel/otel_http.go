@@ -1,13 +1,121 @@
 package otel
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Tracer name of inbound HTTP server spans.
+const httpServerTracerName = "otel/http-server"
+
+// clientIPCtxKey is the context key of client IP.
+type clientIPCtxKey struct{}
+
+// ContextWithClientIP returns a copy of context containing the client IP.
+// Logs written with this context include client_ip field.
+func ContextWithClientIP(ctx context.Context, clientIP string) context.Context {
+	return context.WithValue(ctx, clientIPCtxKey{}, clientIP)
+}
+
+// getClientIPFromCtx returns the client IP in context, empty string if not found.
+func getClientIPFromCtx(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	clientIP, _ := ctx.Value(clientIPCtxKey{}).(string)
+	return clientIP
+}
+
+// ClientIPMiddleware returns Gin middleware putting the real client IP into request context for Logger (see ContextWithClientIP).
+// X-Forwarded-For and X-Real-IP headers are only honored when request comes from a trusted proxy,
+// trustedProxies are IPs or CIDRs (e.g. "10.0.0.0/8"), invalid entries are ignored with a warning.
+// Use it before other middlewares so their logs include client_ip field.
+//
+// Example:
+//
+//	r := gin.New()
+//	r.Use(otel.ClientIPMiddleware("10.0.0.0/8", "127.0.0.1"))
+//	r.Use(otel.GinMiddlewares("api-service")...)
+func ClientIPMiddleware(trustedProxies ...string) gin.HandlerFunc {
+	trustedNets := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, proxy := range trustedProxies {
+		if !strings.Contains(proxy, "/") {
+			if ip := net.ParseIP(proxy); ip != nil {
+				bits := net.IPv6len * 8
+				if ip.To4() != nil {
+					ip, bits = ip.To4(), net.IPv4len*8
+				}
+				trustedNets = append(trustedNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		} else if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			trustedNets = append(trustedNets, ipNet)
+			continue
+		}
+		stdLog.Printf("[warning] Invalid trusted proxy '%s' of ClientIPMiddleware is ignored", proxy)
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, ipNet := range trustedNets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		clientIP := resolveClientIP(c.Request, isTrusted)
+		c.Request = c.Request.WithContext(ContextWithClientIP(c.Request.Context(), clientIP))
+		c.Next()
+	}
+}
+
+// resolveClientIP returns IP of the peer, or the IP reported by proxy headers if the peer is trusted.
+// X-Forwarded-For is walked from right to left, skipping trusted proxies, so spoofed leftmost entries are not used.
+func resolveClientIP(req *http.Request, isTrusted func(ip net.IP) bool) string {
+	remoteIP, _, err := net.SplitHostPort(strings.TrimSpace(req.RemoteAddr))
+	if err != nil {
+		remoteIP = strings.TrimSpace(req.RemoteAddr)
+	}
+	peer := net.ParseIP(remoteIP)
+	if peer == nil || !isTrusted(peer) {
+		return remoteIP
+	}
+
+	if forwardedFor := req.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				break
+			}
+			if i == 0 || !isTrusted(hop) {
+				return hop.String()
+			}
+		}
+	}
+	if realIP := net.ParseIP(strings.TrimSpace(req.Header.Get("X-Real-IP"))); realIP != nil {
+		return realIP.String()
+	}
+
+	return remoteIP
+}
+
 // GinMiddlewares returns Gin middleware for automatic trace propagation.
 // Adds tracing to all HTTP requests handled by Gin router.
 //
@@ -37,3 +145,202 @@ func GinMiddlewares(serviceName string) []gin.HandlerFunc {
 func HttpTransport() *otelhttp.Transport {
 	return otelhttp.NewTransport(http.DefaultTransport)
 }
+
+// HTTPMiddleware returns Gin middleware tracing inbound HTTP requests.
+// It continues the trace propagated in request headers, starts a server Span named by route,
+// records status code and latency, and puts client IP into request context for Logger.
+//
+// Example:
+//
+//	r := gin.New()
+//	r.Use(otel.HTTPMiddleware())
+func HTTPMiddleware() gin.HandlerFunc {
+	tracer := otel.Tracer(httpServerTracerName)
+
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		// Continue trace from request headers
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		clientIP := getClientIPFromCtx(ctx)
+		if clientIP == "" {
+			// Not resolved by ClientIPMiddleware
+			clientIP = c.ClientIP()
+			ctx = ContextWithClientIP(ctx, clientIP)
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unknown"
+		}
+		ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", c.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("http.target", c.Request.URL.Path),
+				attribute.String("client.ip", clientIP),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		statusCode := c.Writer.Status()
+		span.SetAttributes(
+			attribute.Int("http.status_code", statusCode),
+			attribute.Float64("http.latency_ms", float64(time.Since(startTime).Microseconds())/1000),
+		)
+		if len(c.Errors) > 0 {
+			span.RecordError(c.Errors.Last())
+		}
+		if statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(statusCode))
+		}
+	}
+}
+
+// RequestLogField is a field of request log written by RequestLoggingMiddleware.
+type RequestLogField string
+
+// Request log field definitions for RequestLoggingMiddleware.
+const (
+	// REQUEST_LOG_FIELD_METHOD is HTTP method of request.
+	REQUEST_LOG_FIELD_METHOD RequestLogField = "method"
+	// REQUEST_LOG_FIELD_PATH is URL path of request.
+	REQUEST_LOG_FIELD_PATH RequestLogField = "path"
+	// REQUEST_LOG_FIELD_ROUTE is matched route pattern of request (e.g. /users/:id).
+	REQUEST_LOG_FIELD_ROUTE RequestLogField = "route"
+	// REQUEST_LOG_FIELD_STATUS is final status code of response.
+	REQUEST_LOG_FIELD_STATUS RequestLogField = "status"
+	// REQUEST_LOG_FIELD_DURATION_MS is wall time of handling request in milliseconds.
+	REQUEST_LOG_FIELD_DURATION_MS RequestLogField = "duration_ms"
+	// REQUEST_LOG_FIELD_USER_AGENT is User-Agent header of request.
+	REQUEST_LOG_FIELD_USER_AGENT RequestLogField = "user_agent"
+)
+
+// Default fields of request log.
+var defaultRequestLogFields = []RequestLogField{
+	REQUEST_LOG_FIELD_METHOD,
+	REQUEST_LOG_FIELD_PATH,
+	REQUEST_LOG_FIELD_STATUS,
+	REQUEST_LOG_FIELD_DURATION_MS,
+}
+
+// RequestLoggingMiddleware returns Gin middleware writing one structured log per request with the given fields
+// (default: method, path, status, duration_ms). Requests with 5xx status are logged at error level, others at info level.
+// Use it after HTTPMiddleware, so logs are correlated with the request span.
+//
+// Example:
+//
+//	r := gin.New()
+//	r.Use(otel.HTTPMiddleware(), otel.RequestLoggingMiddleware(observer))
+//
+//	// Or log only selected fields
+//	r.Use(otel.HTTPMiddleware(), otel.RequestLoggingMiddleware(observer, otel.REQUEST_LOG_FIELD_METHOD, otel.REQUEST_LOG_FIELD_ROUTE, otel.REQUEST_LOG_FIELD_STATUS))
+func RequestLoggingMiddleware(observer IObserver, fields ...RequestLogField) gin.HandlerFunc {
+	if len(fields) == 0 {
+		fields = defaultRequestLogFields
+	}
+
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		statusCode := c.Writer.Status()
+		attrs := make(map[string]any, len(fields))
+		for _, field := range fields {
+			switch field {
+			case REQUEST_LOG_FIELD_METHOD:
+				{
+					attrs[string(field)] = c.Request.Method
+				}
+			case REQUEST_LOG_FIELD_PATH:
+				{
+					attrs[string(field)] = c.Request.URL.Path
+				}
+			case REQUEST_LOG_FIELD_ROUTE:
+				{
+					attrs[string(field)] = c.FullPath()
+				}
+			case REQUEST_LOG_FIELD_STATUS:
+				{
+					attrs[string(field)] = statusCode
+				}
+			case REQUEST_LOG_FIELD_DURATION_MS:
+				{
+					attrs[string(field)] = float64(time.Since(startTime).Microseconds()) / 1000
+				}
+			case REQUEST_LOG_FIELD_USER_AGENT:
+				{
+					attrs[string(field)] = c.Request.UserAgent()
+				}
+			}
+		}
+
+		ctx := c.Request.Context()
+		if statusCode >= http.StatusInternalServerError {
+			observer.ErrorLogKV(ctx, "HTTP request failed", attrs)
+			return
+		}
+		observer.InfoLogKV(ctx, "HTTP request completed", attrs)
+	}
+}
+
+// RecoveryMiddleware returns Gin middleware recovering panics of following handlers.
+// The panic is recorded as an exception event with stack on the request span and sets its status to error,
+// an error log is written, then respond writes the response (nil: abort with 500 and a plain JSON error).
+// The error passed to respond carries the panic message, keep it out of the response body.
+// Use it after HTTPMiddleware (or GinMiddlewares), so the panic is correlated with the request span.
+//
+// Example:
+//
+//	r := gin.New()
+//	r.Use(otel.HTTPMiddleware(), otel.RecoveryMiddleware(observer, func(c *gin.Context, _ error) {
+//	    c.AbortWithStatusJSON(http.StatusInternalServerError, apperror.ErrInternalServerError(nil, "Internal server error", "ERR_INTERNAL_SERVER_ERROR"))
+//	}))
+func RecoveryMiddleware(observer IObserver, respond func(c *gin.Context, err error)) gin.HandlerFunc {
+	if respond == nil {
+		respond = func(c *gin.Context, err error) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		}
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// Aborted handler is not a crash, let net/http handle it
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			err, ok := recovered.(error)
+			if !ok {
+				err = fmt.Errorf("%v", recovered)
+			}
+			err = fmt.Errorf("panic recovered: %w", err)
+			stack := string(debug.Stack())
+
+			ctx := c.Request.Context()
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(err, trace.WithAttributes(attribute.String("exception.stacktrace", stack)))
+			span.SetStatus(codes.Error, err.Error())
+			markErroredSpan(span)
+
+			observer.ErrorLogKV(ctx, err.Error(), map[string]any{
+				"method": c.Request.Method,
+				"path":   c.Request.URL.Path,
+				"stack":  stack,
+			})
+
+			respond(c, err)
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,223 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newTestMeterObserver creates Observer with Meter of initMeter, metrics are collected by the returned ManualReader.
+func newTestMeterObserver(t testing.TB, metricDefs ...*MetricDef) (*Observer, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	meter, metricCollectorManager, shutdown, err := initMeter(&MeterConfig{
+		ServiceName:              "test-service",
+		EndPoint:                 "localhost:4318",
+		Insecure:                 true,
+		MetricCollectionInterval: time.Hour,
+		MetricDefs:               metricDefs,
+	}, newExportHealth(), reader)
+	if err != nil {
+		t.Fatalf("initMeter: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		shutdown(ctx)
+	})
+
+	return &Observer{meter: meter, metricCollectorManager: metricCollectorManager}, reader
+}
+
+// collectMetric collects metrics of reader and returns the metric with the given name.
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name MetricName) metricdata.Metrics {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name.Get().String() {
+				return m
+			}
+		}
+	}
+	t.Fatalf("metric '%s' not collected", name.Get())
+	return metricdata.Metrics{}
+}
+
+func TestRecordHistogramWithCtxAttachesExemplar(t *testing.T) {
+	observer, reader := newTestMeterObserver(t, &MetricDef{Type: METRIC_TYPE_HISTOGRAM, Name: "latency"})
+
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	defer tracerProvider.Shutdown(context.Background())
+	ctx, span := tracerProvider.Tracer("test").Start(context.Background(), "Request")
+	observer.RecordHistogramWithCtx(ctx, "latency", 123.45, nil)
+	span.End()
+
+	histogram, ok := collectMetric(t, reader, "latency").Data.(metricdata.Histogram[float64])
+	if !ok || len(histogram.DataPoints) != 1 {
+		t.Fatalf("expected 1 histogram data point, got %+v", histogram)
+	}
+	exemplars := histogram.DataPoints[0].Exemplars
+	if len(exemplars) != 1 {
+		t.Fatalf("expected 1 exemplar, got %d", len(exemplars))
+	}
+
+	traceID := span.SpanContext().TraceID()
+	spanID := span.SpanContext().SpanID()
+	if string(exemplars[0].TraceID) != string(traceID[:]) {
+		t.Errorf("exemplar trace_id = %x, expected %s", exemplars[0].TraceID, traceID)
+	}
+	if string(exemplars[0].SpanID) != string(spanID[:]) {
+		t.Errorf("exemplar span_id = %x, expected %s", exemplars[0].SpanID, spanID)
+	}
+}
+
+func TestRecordHistogramWithoutSpanHasNoExemplar(t *testing.T) {
+	observer, reader := newTestMeterObserver(t, &MetricDef{Type: METRIC_TYPE_HISTOGRAM, Name: "latency"})
+
+	observer.RecordHistogram("latency", 123.45, nil)
+
+	histogram := collectMetric(t, reader, "latency").Data.(metricdata.Histogram[float64])
+	if n := len(histogram.DataPoints[0].Exemplars); n != 0 {
+		t.Errorf("expected no exemplar without span, got %d", n)
+	}
+}
+
+func TestRecordCounterFiltersDisallowedAttrs(t *testing.T) {
+	observer, reader := newTestMeterObserver(t, &MetricDef{Type: METRIC_TYPE_COUNTER, Name: "requests", AllowedAttrs: []string{"method"}})
+
+	var logBuf bytes.Buffer
+	stdLog.SetOutput(&logBuf)
+	defer stdLog.SetOutput(os.Stdout)
+
+	for range 3 {
+		observer.RecordCounter("requests", 1, map[string]any{"method": "GET", "user_id": "u1"})
+	}
+
+	sum, ok := collectMetric(t, reader, "requests").Data.(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 {
+		t.Fatalf("expected 1 sum data point, got %+v", sum)
+	}
+	dataPoint := sum.DataPoints[0]
+	if dataPoint.Value != 3 {
+		t.Errorf("value = %d, expected 3", dataPoint.Value)
+	}
+	if value, ok := dataPoint.Attributes.Value("method"); !ok || value.AsString() != "GET" {
+		t.Errorf("allowed attribute 'method' = %v, expected GET", value)
+	}
+	if _, ok := dataPoint.Attributes.Value("user_id"); ok {
+		t.Errorf("disallowed attribute 'user_id' was not dropped")
+	}
+
+	if count := strings.Count(logBuf.String(), "[warning]"); count != 1 {
+		t.Errorf("logged %d warnings, expected 1:\n%s", count, logBuf.String())
+	}
+}
+
+func TestRecordCounterCountsAttrSetsOverCardinality(t *testing.T) {
+	observer, reader := newTestMeterObserver(t, &MetricDef{Type: METRIC_TYPE_COUNTER, Name: "requests", MaxAttrCardinality: 2})
+
+	var logBuf bytes.Buffer
+	stdLog.SetOutput(&logBuf)
+	defer stdLog.SetOutput(os.Stdout)
+
+	for _, tenantID := range []string{"t1", "t2", "t3", "t4", "t5"} {
+		observer.RecordCounter("requests", 1, map[string]any{"tenant_id": tenantID})
+	}
+
+	if count := strings.Count(logBuf.String(), "[warning]"); count != 1 {
+		t.Errorf("logged %d warnings, expected 1:\n%s", count, logBuf.String())
+	}
+
+	sum, ok := collectMetric(t, reader, "requests").Data.(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 2 {
+		t.Fatalf("expected 2 sum data points, got %+v", sum)
+	}
+
+	dropped, ok := collectMetric(t, reader, droppedAttrSetsMetricName).Data.(metricdata.Sum[int64])
+	if !ok || len(dropped.DataPoints) != 1 {
+		t.Fatalf("expected 1 dropped data point, got %+v", dropped)
+	}
+	if value, _ := dropped.DataPoints[0].Attributes.Value("metric"); value.AsString() != MetricName("requests").Get().String() {
+		t.Errorf("dropped attribute metric = %s, expected %s", value.AsString(), MetricName("requests").Get())
+	}
+	if dropped.DataPoints[0].Value != 3 {
+		t.Errorf("dropped = %d, expected 3", dropped.DataPoints[0].Value)
+	}
+}
+
+func BenchmarkRecordGauge(b *testing.B) {
+	observer, _ := newTestMeterObserver(b, &MetricDef{Type: METRIC_TYPE_GAUGE, Name: "queue_size"})
+
+	b.Run("SingleSeries", func(b *testing.B) {
+		attrs := map[string]any{"queue": "default"}
+		b.ReportAllocs()
+		for b.Loop() {
+			observer.RecordGauge("queue_size", 42, attrs)
+		}
+	})
+
+	b.Run("MultiSeries", func(b *testing.B) {
+		attrs := make([]map[string]any, 100)
+		for i := range attrs {
+			attrs[i] = map[string]any{"queue": "queue-" + strconv.Itoa(i)}
+		}
+		b.ReportAllocs()
+		i := 0
+		for b.Loop() {
+			observer.RecordGauge("queue_size", 42, attrs[i%len(attrs)])
+			i++
+		}
+	})
+
+	// Concurrent writers contend on the lock of the gauge state
+	b.Run("Parallel", func(b *testing.B) {
+		attrs := map[string]any{"queue": "default"}
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				observer.RecordGauge("queue_size", 42, attrs)
+			}
+		})
+	})
+}
+
+func BenchmarkRecordCounter(b *testing.B) {
+	observer, _ := newTestMeterObserver(b, &MetricDef{Type: METRIC_TYPE_COUNTER, Name: "requests"})
+	ctx := context.Background()
+
+	// Map is built on every call, as callers of the map-based API do
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			observer.RecordCounterWithCtx(ctx, "requests", 1, map[string]any{"method": "GET", "route": "/users", "status": 200})
+		}
+	})
+
+	// Attribute set is pre-computed once, RecordCounterAttrs skips map conversion
+	b.Run("Attrs", func(b *testing.B) {
+		attrs := []attribute.KeyValue{
+			attribute.String("method", "GET"),
+			attribute.String("route", "/users"),
+			attribute.Int("status", 200),
+		}
+		b.ReportAllocs()
+		for b.Loop() {
+			observer.RecordCounterAttrs(ctx, "requests", 1, attrs...)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// NoopObserver implements IObserver without exporting any telemetry data.
+// Useful for unit testing handlers, services or repositories without OpenTelemetry collector.
+type NoopObserver struct{}
+
+var _ IObserver = (*NoopObserver)(nil)
+
+// NewNoopObserver returns an IObserver that does nothing.
+//
+// Example:
+//
+//	internal.Observer = otel.NewNoopObserver()
+func NewNoopObserver() IObserver {
+	return &NoopObserver{}
+}
+
+// Shutdown does nothing.
+func (o *NoopObserver) Shutdown() {}
+
+// NewSpan returns the given context and a Span which is never exported.
+func (o *NoopObserver) NewSpan(ctx context.Context, operation string) (context.Context, *Span) {
+	span := Span{
+		coreSpan:       noop.Span{},
+		parentCtx:      ctx,
+		spanCtx:        ctx,
+		spanAttributes: make(map[string]any),
+	}
+	return ctx, &span
+}
+
+// NewSpanWithLinks returns the given context and a Span which is never exported.
+func (o *NoopObserver) NewSpanWithLinks(ctx context.Context, operation string, links ...trace.Link) (context.Context, *Span) {
+	return o.NewSpan(ctx, operation)
+}
+
+// Logging functions do nothing.
+
+func (o *NoopObserver) InfoLogWithCtx(ctx context.Context, format string, args ...any)   {}
+func (o *NoopObserver) WarnLogWithCtx(ctx context.Context, format string, args ...any)   {}
+func (o *NoopObserver) DebugLogWithCtx(ctx context.Context, format string, args ...any)  {}
+func (o *NoopObserver) ErrorLogWithCtx(ctx context.Context, format string, args ...any)  {}
+func (o *NoopObserver) InfoLog(format string, args ...any)                               {}
+func (o *NoopObserver) WarnLog(format string, args ...any)                               {}
+func (o *NoopObserver) DebugLog(format string, args ...any)                              {}
+func (o *NoopObserver) ErrorLog(format string, args ...any)                              {}
+func (o *NoopObserver) InfoLogKV(ctx context.Context, msg string, attrs map[string]any)  {}
+func (o *NoopObserver) WarnLogKV(ctx context.Context, msg string, attrs map[string]any)  {}
+func (o *NoopObserver) DebugLogKV(ctx context.Context, msg string, attrs map[string]any) {}
+func (o *NoopObserver) ErrorLogKV(ctx context.Context, msg string, attrs map[string]any) {}
+
+// WithLogGroup returns the NoopObserver itself.
+func (o *NoopObserver) WithLogGroup(name string) IObserver { return o }
+
+// Metric functions do nothing.
+
+func (o *NoopObserver) UnregisterMetric(name MetricName) error { return nil }
+
+func (o *NoopObserver) RecordCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordUpDownCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordHistogramWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordGaugeWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordIntGaugeWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordCounter(name MetricName, value int64, metricAttrs map[string]any) {}
+func (o *NoopObserver) RecordUpDownCounter(name MetricName, value int64, metricAttrs map[string]any) {
+}
+func (o *NoopObserver) RecordHistogram(name MetricName, value float64, metricAttrs map[string]any) {}
+func (o *NoopObserver) RecordGauge(name MetricName, value float64, metricAttrs map[string]any)     {}
+func (o *NoopObserver) RecordIntGauge(name MetricName, value int64, metricAttrs map[string]any)    {}
+func (o *NoopObserver) RecordCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+}
+func (o *NoopObserver) RecordUpDownCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+}
+func (o *NoopObserver) RecordHistogramAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue) {
+}
+func (o *NoopObserver) RecordGaugeAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue) {
+}
+func (o *NoopObserver) RecordIntGaugeAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+}
+func (o *NoopObserver) HistogramSnapshot(name MetricName) (int64, float64) { return 0, 0 }
+
+// Cache functions do nothing and never fail, getting a Trace Carrier always returns an empty one.
+
+func (o *NoopObserver) GetCacheTraceCarrierFromGroup(group string, key string) (TraceCarrier, error) {
+	return TraceCarrier{}, nil
+}
+func (o *NoopObserver) SetCacheTraceCarrierFromGroup(group string, key string, traceCarrier TraceCarrier) error {
+	return nil
+}
+func (o *NoopObserver) DeleteCacheTraceCarrierFromGroup(group string, key string) error { return nil }
+func (o *NoopObserver) DeleteCacheTraceCarrierGroup(group string) error                 { return nil }
+func (o *NoopObserver) DeleteCacheTraceCarrierGroupAndNotify(group string, channel string) error {
+	return nil
+}
+func (o *NoopObserver) ClearCacheTraceCarrier() error { return nil }
+func (o *NoopObserver) ClearCacheTraceCarrierOlderThan(age time.Duration) (int, error) {
+	return 0, nil
+}
+func (o *NoopObserver) ListCacheTraceCarrierKeysInGroup(group string) ([]string, error) {
+	return []string{}, nil
+}
+func (o *NoopObserver) CountCacheTraceCarrierGroup(group string) (int64, error) { return 0, nil }
+
+// HealthCheck always succeeds.
+func (o *NoopObserver) HealthCheck(ctx context.Context) error { return nil }
+
+// DiagnosticsSnapshot returns empty Diagnostics.
+func (o *NoopObserver) DiagnosticsSnapshot(ctx context.Context) Diagnostics { return Diagnostics{} }
@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestShutdownShutsDownTracerLast(t *testing.T) {
+	var calls []string
+	record := func(name string) func(context.Context) {
+		return func(context.Context) { calls = append(calls, name) }
+	}
+
+	// Tracer option is applied first, as in NewOtelObserver(WithTracer(...), WithLogger(...), WithMeter(...))
+	observer := &Observer{
+		shutdowns: []shutdownFunc{
+			{kind: signalKindTracer, shutdown: record("tracer")},
+			{kind: signalKindLogger, shutdown: record("logger")},
+			{kind: signalKindMeter, shutdown: record("prometheus")},
+			{kind: signalKindMeter, shutdown: record("meter")},
+		},
+	}
+	observer.Shutdown()
+
+	expected := []string{"prometheus", "meter", "logger", "tracer"}
+	if !slices.Equal(calls, expected) {
+		t.Errorf("shutdown order = %v, expected %v", calls, expected)
+	}
+}
+
+func TestResetObserverClearsGlobalState(t *testing.T) {
+	observer, err := NewOtelObserver(
+		WithTracer(&TracerConfig{ServiceName: "test-service", EndPoint: "localhost:4318", Insecure: true, SampleRatio: 0.5}, WithErrorSampling()),
+	)
+	if err != nil {
+		t.Fatalf("NewOtelObserver: %v", err)
+	}
+	SetLogLevel(LOG_LEVEL_DEBUG)
+
+	tracerProvider, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	if !ok || erroredTraces.Load() == nil {
+		t.Fatalf("expected SDK Tracer provider with error sampling before reset")
+	}
+
+	ResetObserver(observer)
+
+	if _, ok := otel.GetTracerProvider().(tracenoop.TracerProvider); !ok {
+		t.Errorf("global Tracer provider = %T, expected noop", otel.GetTracerProvider())
+	}
+	if len(otel.GetTextMapPropagator().Fields()) != 0 {
+		t.Errorf("global propagator fields = %v, expected none", otel.GetTextMapPropagator().Fields())
+	}
+	if erroredTraces.Load() != nil {
+		t.Errorf("errored traces are not cleared")
+	}
+	if level := logLevelVar.Level(); level != slog.LevelInfo {
+		t.Errorf("log level = %v, expected INFO", level)
+	}
+	if observer.tracer != nil || observer.exportHealth != nil || len(observer.shutdowns) != 0 {
+		t.Errorf("observer is not cleared: %+v", observer)
+	}
+
+	// Previous provider is shut down, new spans are not recorded
+	_, span := tracerProvider.Tracer("test").Start(context.Background(), "after-reset")
+	if span.IsRecording() {
+		t.Errorf("span of previous Tracer provider is recording after reset")
+	}
+
+	// Error handler no longer references the reset Observer
+	otel.Handle(errors.New("after reset"))
+}
@@ -19,7 +19,22 @@ import (
 //	defer span.Done()
 //	span.SetAttribute("query", "SELECT * FROM users")
 func (o *Observer) NewSpan(ctx context.Context, operation string) (context.Context, *Span) {
-	spanCtx, coreSpan := o.tracer.Start(ctx, operation, trace.WithTimestamp(time.Now()))
+	return o.NewSpanWithLinks(ctx, operation)
+}
+
+// NewSpanWithLinks creates a new tracing Span for the given operation with links to other spans.
+// Links express causal relationships which are not parent-child (e.g. fan-out jobs referencing their trigger).
+//
+// Example:
+//
+//	ctx, span := observer.NewSpanWithLinks(context.Background(), "worker.process", carrier.ToLink())
+//	defer span.Done()
+func (o *Observer) NewSpanWithLinks(ctx context.Context, operation string, links ...trace.Link) (context.Context, *Span) {
+	spanCtx, coreSpan := o.tracer.Start(ctx, operation,
+		trace.WithTimestamp(time.Now()),
+		trace.WithLinks(links...),
+		trace.WithAttributes(getIdentityAttrs(ctx)...),
+	)
 
 	span := Span{
 		coreSpan:       coreSpan,
@@ -38,6 +53,7 @@ type Span struct {
 	parentCtx context.Context // Parent context of this Span
 	spanCtx   context.Context // Context containing this Span
 	err       error           // Error to be recorded when Span ends
+	failed    bool            // Error status is already set by RecordError
 
 	spanAttributes map[string]any // Attributes to be added to the Span
 }
@@ -59,8 +75,10 @@ func (span *Span) Done() {
 		span.coreSpan.SetStatus(codes.Error, span.err.Error())
 		span.coreSpan.End(trace.WithStackTrace(true))
 	} else {
-		// Set success status
-		span.coreSpan.SetStatus(codes.Ok, "success")
+		// Set success status, unless an error is already recorded
+		if !span.failed {
+			span.coreSpan.SetStatus(codes.Ok, "success")
+		}
 		span.coreSpan.End()
 	}
 }
@@ -78,9 +96,31 @@ func (span *Span) Context() context.Context {
 }
 
 // SetError marks the Span as failed.
-// The error will be recorded when Done() is called.
+// The error will be recorded when Done() is called, with error sampling (see WithErrorSampling) the trace is sampled from now on.
 func (span *Span) SetError(err error) {
 	span.err = err
+	if err != nil {
+		markErroredSpan(span.coreSpan)
+	}
+}
+
+// RecordError records err with attributes on the Span immediately and sets error status,
+// unlike SetError which records only when Done() is called. Done() still finalizes the Span.
+//
+// Example:
+//
+//	if err != nil {
+//	    span.RecordError(err, map[string]any{"downstream": "service-b", "http.status_code": 502})
+//	}
+func (span *Span) RecordError(err error, attrs map[string]any) {
+	if err == nil {
+		return
+	}
+
+	span.coreSpan.RecordError(err, trace.WithAttributes(mapToAttribute(attrs)...))
+	span.coreSpan.SetStatus(codes.Error, err.Error())
+	span.failed = true
+	markErroredSpan(span.coreSpan)
 }
 
 // SetAttribute adds a key-value attribute to the Span.
@@ -90,6 +130,32 @@ func (span *Span) SetAttribute(key string, value any) {
 	span.spanAttributes[key] = value
 }
 
+// SetAttributes adds multiple key-value attributes to the Span.
+// Existing attributes with the same keys are overwritten.
+//
+// Example:
+//
+//	span.SetAttributes(map[string]any{"user_id": 123, "role": "admin"})
+func (span *Span) SetAttributes(attributes map[string]any) {
+	for key, value := range attributes {
+		span.spanAttributes[key] = value
+	}
+}
+
+// LinkTo adds a link from the Span to the span carried by the given Trace Carrier.
+// Empty or invalid Trace Carrier is ignored.
+//
+// Example:
+//
+//	span.LinkTo(carrier)
+func (span *Span) LinkTo(traceCarrier TraceCarrier) {
+	link := traceCarrier.ToLink()
+	if !link.SpanContext.IsValid() {
+		return
+	}
+	span.coreSpan.AddLink(link)
+}
+
 // AddEvent records a point-in-time event within the Span.
 // Useful for marking important moments like cache hits or retry attempts.
 //
@@ -130,6 +196,14 @@ func (traceCarrier TraceCarrier) ExtractContext() context.Context {
 	return otel.GetTextMapPropagator().Extract(context.Background(), propagation.MapCarrier(traceCarrier))
 }
 
+// ToLink converts the Trace Carrier into a span link.
+// Use it with NewSpanWithLinks to reference the span carried by Trace Carrier.
+func (traceCarrier TraceCarrier) ToLink() trace.Link {
+	return trace.Link{
+		SpanContext: trace.SpanContextFromContext(traceCarrier.ExtractContext()),
+	}
+}
+
 // IsZero reports whether the TraceCarrier contains no propagation data.
 //
 // It returns true when the carrier is either nil or empty (len == 0).
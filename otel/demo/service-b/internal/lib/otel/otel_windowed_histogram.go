@@ -0,0 +1,111 @@
+package otel
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Default settings of WindowedHistogram.
+const (
+	defaultHistogramWindow     = time.Minute
+	defaultHistogramMaxSamples = 1024
+)
+
+// WindowedHistogram keeps samples recorded within a sliding window for computing recent percentiles locally,
+// samples older than window are expired and only the most recent maxSamples are kept.
+// Unlike LatencyTracker, it doesn't forward values to any registered histogram.
+type WindowedHistogram struct {
+	name   string
+	window time.Duration
+
+	samples []windowedSample // Ring buffer of samples ordered by recorded time
+	first   int              // Index of the oldest sample in ring buffer
+	count   int              // Number of samples in ring buffer
+	mu      sync.Mutex
+}
+
+type windowedSample struct {
+	recordedAt time.Time
+	value      float64
+}
+
+// NewWindowedHistogram creates a WindowedHistogram keeping samples of the last window (<= 0: 1m),
+// at most maxSamples (<= 0: 1024) most recent samples are kept, older ones are dropped even if they are in window.
+//
+// Example:
+//
+//	histogram := otel.NewWindowedHistogram("request_latency", 30*time.Second, 1000)
+//	histogram.Record(123.45)
+//	p50, p95, p99, count := histogram.Snapshot()
+//	observer.InfoLog("last 30s: p50=%v p95=%v p99=%v count=%v", p50, p95, p99, count)
+func NewWindowedHistogram(name string, window time.Duration, maxSamples int) *WindowedHistogram {
+	if window <= 0 {
+		window = defaultHistogramWindow
+	}
+	if maxSamples <= 0 {
+		maxSamples = defaultHistogramMaxSamples
+	}
+
+	return &WindowedHistogram{
+		name:    name,
+		window:  window,
+		samples: make([]windowedSample, maxSamples),
+	}
+}
+
+// Name returns name of the histogram.
+func (wh *WindowedHistogram) Name() string {
+	return wh.name
+}
+
+// Record keeps the value in window, the oldest sample is dropped if maxSamples is reached.
+func (wh *WindowedHistogram) Record(value float64) {
+	now := time.Now()
+
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	wh.expire(now)
+	sample := windowedSample{recordedAt: now, value: value}
+	if wh.count == len(wh.samples) {
+		wh.samples[wh.first] = sample
+		wh.first = (wh.first + 1) % len(wh.samples)
+		return
+	}
+	wh.samples[(wh.first+wh.count)%len(wh.samples)] = sample
+	wh.count++
+}
+
+// Snapshot returns p50, p95, p99 (see LatencyTracker.Percentile) and number of samples in window.
+// Returns zeros if window is empty.
+func (wh *WindowedHistogram) Snapshot() (float64, float64, float64, int) {
+	wh.mu.Lock()
+	wh.expire(time.Now())
+	sorted := make([]float64, wh.count)
+	for i := range sorted {
+		sorted[i] = wh.samples[(wh.first+i)%len(wh.samples)].value
+	}
+	wh.mu.Unlock()
+
+	sort.Float64s(sorted)
+	return percentileOfSorted(sorted, 50), percentileOfSorted(sorted, 95), percentileOfSorted(sorted, 99), len(sorted)
+}
+
+// Reset drops all samples in window.
+func (wh *WindowedHistogram) Reset() {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+
+	wh.first = 0
+	wh.count = 0
+}
+
+// expire drops samples recorded before now - window, must be called with lock held.
+func (wh *WindowedHistogram) expire(now time.Time) {
+	cutoff := now.Add(-wh.window)
+	for wh.count > 0 && !wh.samples[wh.first].recordedAt.After(cutoff) {
+		wh.first = (wh.first + 1) % len(wh.samples)
+		wh.count--
+	}
+}
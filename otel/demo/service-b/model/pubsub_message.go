@@ -1,9 +1,19 @@
 package model
 
-import "thanhldt060802/internal/lib/otel"
+import (
+	"context"
+	"thanhldt060802/internal/lib/otel"
+)
 
 type ExamplePubSubMessage struct {
 	otel.TraceCarrier `json:"trace_carrier"`
 
 	ExampleUuid string `json:"example_uuid"`
 }
+
+// InjectTraceCarrier sets Trace Carrier from ctx when the message does not carry one.
+func (message *ExamplePubSubMessage) InjectTraceCarrier(ctx context.Context) {
+	if message.TraceCarrier.IsZero() {
+		message.TraceCarrier = otel.ExportTraceCarrier(ctx)
+	}
+}
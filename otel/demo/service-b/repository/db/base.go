@@ -0,0 +1,114 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"thanhldt060802/internal"
+	"thanhldt060802/internal/sqlclient"
+	"thanhldt060802/repository"
+
+	"github.com/uptrace/bun"
+)
+
+// BaseRepo implements common operations of a bun model T, all queries are traced by Observer.
+// Repository of an entity embeds it and only adds its own queries.
+//
+// Example:
+//
+//	type ExampleRepo struct {
+//	    *BaseRepo[model.Example]
+//	}
+//
+//	repo := &ExampleRepo{BaseRepo: NewBaseRepo[model.Example]("Example")}
+//	example, err := repo.BaseRepo.GetById(ctx, "example_uuid", exampleUuid)
+type BaseRepo[T any] struct {
+	entityName string // Name of entity in span names and logs (e.g. "Example")
+}
+
+func NewBaseRepo[T any](entityName string) *BaseRepo[T] {
+	return &BaseRepo[T]{
+		entityName: entityName,
+	}
+}
+
+// DropTable drops table of T if it exists.
+func (repo *BaseRepo[T]) DropTable(ctx context.Context) error {
+	return repository.DropTable(sqlclient.SqlClientConnInstance, ctx, (*T)(nil))
+}
+
+// CreateTable creates table of T if it doesn't exist.
+func (repo *BaseRepo[T]) CreateTable(ctx context.Context) error {
+	return repository.CreateTable(sqlclient.SqlClientConnInstance, ctx, (*T)(nil))
+}
+
+// GetById returns the entity whose idField equals idValue, nil if not found.
+func (repo *BaseRepo[T]) GetById(ctx context.Context, idField string, idValue any) (*T, error) {
+	ctx, span := internal.Observer.NewSpan(ctx, fmt.Sprintf("Get%sById-Repository", repo.entityName))
+	defer span.Done()
+
+	internal.Observer.InfoLogWithCtx(ctx, "[Repository layer] Get %s by %s='%v'", repo.entityName, idField, idValue)
+
+	entity := new(T)
+
+	query := sqlclient.SqlClientConnInstance.GetDB().NewSelect().Model(entity).
+		Where("? = ?", bun.Ident(idField), idValue)
+
+	span.AddEvent("Execute SQL", map[string]any{
+		"sql": query.String(),
+	})
+
+	err := query.Scan(ctx)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		internal.Observer.ErrorLogWithCtx(ctx, "[Repository layer] Failed to get %s by %s='%v'", repo.entityName, idField, idValue)
+		span.SetError(err)
+		return nil, err
+	} else {
+		return entity, nil
+	}
+}
+
+// Create inserts the entity.
+func (repo *BaseRepo[T]) Create(ctx context.Context, entity *T) error {
+	ctx, span := internal.Observer.NewSpan(ctx, fmt.Sprintf("Create%s-Repository", repo.entityName))
+	defer span.Done()
+
+	internal.Observer.InfoLogWithCtx(ctx, "[Repository layer] Create %s", repo.entityName)
+
+	query := sqlclient.SqlClientConnInstance.GetDB().NewInsert().Model(entity)
+
+	span.AddEvent("Execute SQL", map[string]any{
+		"sql": query.String(),
+	})
+
+	if _, err := query.Exec(ctx); err != nil {
+		internal.Observer.ErrorLogWithCtx(ctx, "[Repository layer] Failed to create %s", repo.entityName)
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
+
+// Delete deletes entities whose idField equals idValue.
+func (repo *BaseRepo[T]) Delete(ctx context.Context, idField string, idValue any) error {
+	ctx, span := internal.Observer.NewSpan(ctx, fmt.Sprintf("Delete%sById-Repository", repo.entityName))
+	defer span.Done()
+
+	internal.Observer.InfoLogWithCtx(ctx, "[Repository layer] Delete %s by %s='%v'", repo.entityName, idField, idValue)
+
+	query := sqlclient.SqlClientConnInstance.GetDB().NewDelete().Model((*T)(nil)).
+		Where("? = ?", bun.Ident(idField), idValue)
+
+	span.AddEvent("Execute SQL", map[string]any{
+		"sql": query.String(),
+	})
+
+	if _, err := query.Exec(ctx); err != nil {
+		internal.Observer.ErrorLogWithCtx(ctx, "[Repository layer] Failed to delete %s by %s='%v'", repo.entityName, idField, idValue)
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
@@ -3,67 +3,411 @@ package pubsub
 import (
 	"context"
 	"encoding/json"
-	"reflect"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"thanhldt060802/internal"
 	"thanhldt060802/model"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 )
 
-var RedisSubInstance IRedisSub[*model.ExamplePubSubMessage]
+var RedisSubInstance IRedisSub[model.ExamplePubSubMessage]
+
+var (
+	ErrSubscriberWaitTimeout = errors.New("timeout waiting for subscriber to stop")
+	ErrRedisSubClosed        = errors.New("redis sub is closed")
+)
+
+const (
+	reconnectBaseDelay  = 500 * time.Millisecond
+	reconnectMaxDelay   = 30 * time.Second
+	receiveCheckTimeout = 5 * time.Second
+
+	// defaultDeadLetterSuffix is appended to channel name to get its dead-letter channel.
+	defaultDeadLetterSuffix = ":dlq"
+)
 
 type IRedisSub[T any] interface {
-	Subscribe(ctx context.Context, channel string, handler func(data T))
+	Subscribe(channel string, handler func(ctx context.Context, msg *T) error) error
+	Run(ctx context.Context) error
+	Start(ctx context.Context) *SubscriberHandle
+	Close() error
+}
+
+// SubscriberHandle is a subscriber running in background (see Start).
+type SubscriberHandle struct {
+	done chan struct{}
+	err  error
+}
+
+// Wait blocks until subscriber stops (after ctx passed to Start is done) and its in-flight handlers return.
+// Returns ErrSubscriberWaitTimeout if subscriber doesn't stop within timeout.
+func (handle *SubscriberHandle) Wait(timeout time.Duration) error {
+	select {
+	case <-handle.done:
+		{
+			return handle.err
+		}
+	case <-time.After(timeout):
+		{
+			return ErrSubscriberWaitTimeout
+		}
+	}
 }
 
 type RedisSub[T any] struct {
-	client *redis.Client
+	client      *redis.Client
+	deadLetter  deadLetter
+	concurrency map[string]int                  // Number of handler goroutines of channel, channels not in map have one goroutine
+	onError     func(channel string, err error) // Called when message fails to be decoded or handled
+
+	mu       sync.RWMutex
+	handlers map[string]func(ctx context.Context, msg *T) error
+	sub      *redis.PubSub // Current subscription while Run is consuming, nil otherwise
+	closed   bool
+
+	closeCtx    context.Context // Done when Close is called, stops all Run
+	closeCancel context.CancelFunc
+	running     sync.WaitGroup // Running Run calls
 }
 
-func NewRedisSub[T any](client *redis.Client) IRedisSub[T] {
+// deadLetter configures handling of failed messages, failed messages are only logged if maxAttempts is 0.
+type deadLetter struct {
+	suffix      string // Dead-letter channel of a channel is "{channel}{suffix}"
+	maxAttempts int    // Number of handler attempts before message is dead-lettered
+}
+
+// redisSubOptions holds settings customized by RedisSubOption.
+type redisSubOptions struct {
+	deadLetter  deadLetter
+	concurrency map[string]int
+	onError     func(channel string, err error)
+}
+
+// RedisSubOption customizes Redis Sub.
+type RedisSubOption func(opts *redisSubOptions)
+
+// WithDeadLetter retries failed handler up to maxAttempts times in total, then republishes the raw message
+// to "{channel}{suffix}" (empty suffix: ":dlq") with added "error" and "attempts" fields for inspection and replay.
+// Messages failed to be decoded are dead-lettered with attempts 0.
+func WithDeadLetter(suffix string, maxAttempts int) RedisSubOption {
+	return func(opts *redisSubOptions) {
+		if suffix == "" {
+			suffix = defaultDeadLetterSuffix
+		}
+		opts.deadLetter.suffix = suffix
+		opts.deadLetter.maxAttempts = max(maxAttempts, 1)
+	}
+}
+
+// WithConcurrency handles messages of channel by up to n goroutines (n <= 1: one goroutine, in order), so messages of channel are no longer handled in order.
+// Messages are read from Redis only when a goroutine is free, so a slow channel slows down reading of all channels instead of buffering messages in memory.
+func WithConcurrency(channel string, n int) RedisSubOption {
+	return func(opts *redisSubOptions) {
+		if opts.concurrency == nil {
+			opts.concurrency = map[string]int{}
+		}
+		opts.concurrency[channel] = n
+	}
+}
+
+// WithErrorHandler calls onError with channel and error when a message fails to be decoded or its handler
+// still fails after all attempts, before it is dead-lettered (default: error is logged).
+func WithErrorHandler(onError func(channel string, err error)) RedisSubOption {
+	return func(opts *redisSubOptions) {
+		opts.onError = onError
+	}
+}
+
+func NewRedisSub[T any](client *redis.Client, opts ...RedisSubOption) IRedisSub[T] {
+	subOpts := redisSubOptions{}
+	for _, opt := range opts {
+		opt(&subOpts)
+	}
+	if subOpts.onError == nil {
+		subOpts.onError = func(channel string, err error) {
+			log.Errorf("Handle message of channel '%v' failed: %v", channel, err.Error())
+		}
+	}
+
+	closeCtx, closeCancel := context.WithCancel(context.Background())
 	return &RedisSub[T]{
-		client: client,
+		client:      client,
+		deadLetter:  subOpts.deadLetter,
+		concurrency: subOpts.concurrency,
+		onError:     subOpts.onError,
+		handlers:    make(map[string]func(ctx context.Context, msg *T) error),
+		closeCtx:    closeCtx,
+		closeCancel: closeCancel,
 	}
 }
 
-func (redisSub *RedisSub[T]) Subscribe(ctx context.Context, channel string, handler func(data T)) {
-	sub := redisSub.client.Subscribe(ctx, channel)
-	ch := sub.Channel()
-	go func() {
-		for {
+// Subscribe registers handler for the channel, it can be called many times with distinct channels.
+// Messages are consumed when Run is called, channel subscribed while Run is consuming is subscribed right away.
+// Message is failed if handler returns error (see WithDeadLetter and WithErrorHandler).
+func (redisSub *RedisSub[T]) Subscribe(channel string, handler func(ctx context.Context, msg *T) error) error {
+	redisSub.mu.Lock()
+	defer redisSub.mu.Unlock()
+
+	if redisSub.closed {
+		return ErrRedisSubClosed
+	}
+	if _, ok := redisSub.handlers[channel]; ok {
+		return fmt.Errorf("channel '%v' is already subscribed", channel)
+	}
+
+	if redisSub.sub != nil {
+		if err := redisSub.sub.Subscribe(redisSub.closeCtx, channel); err != nil {
+			log.Errorf("Subscribe channel '%v' failed: %v", channel, err.Error())
+			return err
+		}
+	}
+	redisSub.handlers[channel] = handler
+	return nil
+}
+
+// Close stops all Run, unsubscribes all channels and waits for in-flight handlers to return.
+// Subscribe and Run return ErrRedisSubClosed after Close.
+func (redisSub *RedisSub[T]) Close() error {
+	redisSub.mu.Lock()
+	redisSub.closed = true
+	redisSub.mu.Unlock()
+
+	redisSub.closeCancel()
+	redisSub.running.Wait()
+	return nil
+}
+
+// Run subscribes all registered channels and consumes messages until ctx is done or Close is called.
+// When the connection is broken, it reconnects with capped and jittered exponential backoff, then resubscribes all channels.
+func (redisSub *RedisSub[T]) Run(ctx context.Context) error {
+	redisSub.mu.Lock()
+	if redisSub.closed {
+		redisSub.mu.Unlock()
+		return ErrRedisSubClosed
+	}
+	redisSub.running.Add(1)
+	redisSub.mu.Unlock()
+	defer redisSub.running.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(redisSub.closeCtx, cancel)
+	defer stop()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			delay := reconnectDelay(attempt)
+			internal.Observer.WarnLog("[Redis Sub] Reconnecting attempt %d in %v", attempt, delay)
+
 			select {
 			case <-ctx.Done():
-				sub.Close()
-				return
-			case message := <-ch:
-				var value T
-				t := reflect.TypeOf(value)
-
-				var instance any
-				if t.Kind() == reflect.Ptr {
-					// T is pointer to struct: create *Struct
-					instance = reflect.New(t.Elem()).Interface()
-				} else {
-					// T is value: create pointer to value (e.g., *int, *string)
-					instance = reflect.New(t).Interface()
-				}
+				return nil
+			case <-time.After(delay):
+			}
+		}
 
-				if err := json.Unmarshal([]byte(message.Payload), instance); err != nil {
-					log.Errorf("Unmarshal %v failed: %v", message.Payload, err.Error())
-					continue
-				}
+		consumed, err := redisSub.consume(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if consumed {
+			// Connection was healthy before it broke, restart backoff
+			attempt = 0
+		}
+		internal.Observer.ErrorLog("[Redis Sub] Subscription is broken: %v", err)
+	}
+}
+
+// Start runs subscriber in background (callback: Run), cancelling ctx stops pulling new messages.
+//
+// Example:
+//
+//	handle := redisSub.Start(ctx)
+//	<-ctx.Done()
+//	if err := handle.Wait(10 * time.Second); err != nil {
+//	    ...
+//	}
+func (redisSub *RedisSub[T]) Start(ctx context.Context) *SubscriberHandle {
+	handle := &SubscriberHandle{
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(handle.done)
+		handle.err = redisSub.Run(ctx)
+	}()
+
+	return handle
+}
+
+// consume subscribes all registered channels and handles messages until ctx is done or the connection is broken.
+// Returns whether subscription was established successfully.
+func (redisSub *RedisSub[T]) consume(ctx context.Context) (bool, error) {
+	redisSub.mu.RLock()
+	channels := make([]string, 0, len(redisSub.handlers))
+	for channel := range redisSub.handlers {
+		channels = append(channels, channel)
+	}
+	redisSub.mu.RUnlock()
+
+	sub := redisSub.client.Subscribe(ctx, channels...)
+	defer sub.Close()
+
+	// Wait for confirmation of subscription
+	if _, err := sub.Receive(ctx); err != nil {
+		return false, err
+	}
+	internal.Observer.InfoLog("[Redis Sub] Subscribed channels %v", channels)
+
+	// Channels subscribed from now on are subscribed on sub by Subscribe
+	redisSub.mu.Lock()
+	redisSub.sub = sub
+	redisSub.mu.Unlock()
+	defer func() {
+		redisSub.mu.Lock()
+		redisSub.sub = nil
+		redisSub.mu.Unlock()
+	}()
 
-				var data T
-				if t.Kind() == reflect.Ptr {
-					// T is pointer already
-					data = instance.(T)
-				} else {
-					// T is value, dereference pointer
-					data = reflect.ValueOf(instance).Elem().Interface().(T)
+	pools := redisSub.startWorkerPools(ctx, channels)
+	// Stop pulling first, then let workers finish queued and in-flight messages before subscription is closed
+	defer pools.stop()
+
+	for ctx.Err() == nil {
+		msg, err := sub.ReceiveTimeout(ctx, receiveCheckTimeout)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				// No message in a while, make sure connection is still alive
+				if err := sub.Ping(ctx); err != nil {
+					return true, err
 				}
+				continue
+			}
+			return true, err
+		}
 
-				handler(data)
+		if message, ok := msg.(*redis.Message); ok {
+			if queue, ok := pools.queues[message.Channel]; ok {
+				// Blocks while all workers of channel are busy (backpressure)
+				queue <- message
+			} else {
+				// Channel subscribed after workers were started, it gets its own workers on reconnect
+				redisSub.handle(ctx, message)
 			}
 		}
-	}()
+	}
+
+	return true, nil
+}
+
+// workerPools is the set of bounded worker pools of channels configured by WithConcurrency.
+type workerPools struct {
+	queues map[string]chan *redis.Message
+	wg     sync.WaitGroup
+}
+
+// startWorkerPools starts worker goroutines of all channels, one goroutine per channel unless WithConcurrency is set.
+func (redisSub *RedisSub[T]) startWorkerPools(ctx context.Context, channels []string) *workerPools {
+	pools := &workerPools{
+		queues: map[string]chan *redis.Message{},
+	}
+
+	for _, channel := range channels {
+		n := max(redisSub.concurrency[channel], 1)
+
+		queue := make(chan *redis.Message)
+		pools.queues[channel] = queue
+		for i := 0; i < n; i++ {
+			pools.wg.Add(1)
+			go func() {
+				defer pools.wg.Done()
+				for message := range queue {
+					redisSub.handle(ctx, message)
+				}
+			}()
+		}
+	}
+
+	return pools
+}
+
+// stop closes queues of worker pools and waits for workers to finish their messages.
+func (pools *workerPools) stop() {
+	for _, queue := range pools.queues {
+		close(queue)
+	}
+	pools.wg.Wait()
+}
+
+func (redisSub *RedisSub[T]) handle(ctx context.Context, message *redis.Message) {
+	redisSub.mu.RLock()
+	handler, ok := redisSub.handlers[message.Channel]
+	redisSub.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	data := new(T)
+	if err := json.Unmarshal([]byte(message.Payload), data); err != nil {
+		redisSub.onError(message.Channel, fmt.Errorf("unmarshal %v failed: %w", message.Payload, err))
+		redisSub.publishDeadLetter(ctx, message, err, 0)
+		return
+	}
+
+	attempts := max(redisSub.deadLetter.maxAttempts, 1)
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = handler(ctx, data); err == nil {
+			return
+		}
+		if attempt < attempts {
+			log.Warnf("Handle message of channel '%v' failed (attempt %v/%v): %v", message.Channel, attempt, attempts, err.Error())
+		}
+	}
+
+	redisSub.onError(message.Channel, err)
+	redisSub.publishDeadLetter(ctx, message, err, attempts)
+}
+
+// publishDeadLetter republishes raw message to dead-letter channel with "error" and "attempts" fields, does nothing if dead-letter is not configured.
+// Non-object payload is kept under "payload" field.
+func (redisSub *RedisSub[T]) publishDeadLetter(ctx context.Context, message *redis.Message, cause error, attempts int) {
+	if redisSub.deadLetter.maxAttempts == 0 {
+		return
+	}
+
+	fields := map[string]any{}
+	if err := json.Unmarshal([]byte(message.Payload), &fields); err != nil || fields == nil {
+		fields = map[string]any{"payload": message.Payload}
+	}
+	fields["error"] = cause.Error()
+	fields["attempts"] = attempts
+
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		log.Errorf("Marshal dead-letter of channel '%v' failed: %v", message.Channel, err.Error())
+		return
+	}
+
+	// Dead-letter is still published while subscriber is shutting down
+	channel := message.Channel + redisSub.deadLetter.suffix
+	if err := redisSub.client.Publish(context.WithoutCancel(ctx), channel, payload).Err(); err != nil {
+		log.Errorf("Publish dead-letter to channel '%v' failed: %v", channel, err.Error())
+	}
+}
+
+// reconnectDelay returns exponential backoff delay of the attempt, capped by reconnectMaxDelay and jittered.
+func reconnectDelay(attempt int) time.Duration {
+	delay := reconnectMaxDelay
+	if attempt < 16 {
+		delay = min(reconnectBaseDelay<<(attempt-1), reconnectMaxDelay)
+	}
+	return delay/2 + rand.N(delay/2+1)
 }
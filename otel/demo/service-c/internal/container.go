@@ -2,4 +2,4 @@ package internal
 
 import "thanhldt060802/internal/lib/otel"
 
-var Observer *otel.Observer
+var Observer otel.IObserver
@@ -0,0 +1,93 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ExtractFromAsynqTask returns a copy of context continuing the trace carried by "trace_carrier" field of task payload
+// (e.g. a JSON payload with a TraceCarrier field tagged `json:"trace_carrier"`).
+// Only "trace_carrier" field is decoded, so payload type doesn't need to be known.
+// Returns ctx unchanged if task payload carries no trace context.
+//
+// Example:
+//
+//	mux.HandleFunc("myqueuetask:hello", func(ctx context.Context, task *asynq.Task) error {
+//	    ctx, span := observer.NewSpan(otel.ExtractFromAsynqTask(ctx, task), "HelloTask")
+//	    defer span.Done()
+//	    ...
+//	})
+func ExtractFromAsynqTask(ctx context.Context, task *asynq.Task) context.Context {
+	var payload struct {
+		TraceCarrier TraceCarrier `json:"trace_carrier"`
+	}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil || payload.TraceCarrier.IsZero() {
+		return ctx
+	}
+
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(payload.TraceCarrier))
+}
+
+// AsynqMiddleware returns asynq worker middleware wrapping each handler in a span named by task type.
+// The span continues the trace of enqueuer (see ExtractFromAsynqTask) and handler error is set on it.
+// Processing latency (milliseconds) is recorded to latencyHistogram by "task_type", "queue" and "status" ("success" or "failed"),
+// latencyHistogram must be registered as METRIC_TYPE_HISTOGRAM (see WithMeter).
+//
+// Example:
+//
+//	observer, err := otel.NewOtelObserver(
+//	    otel.WithTracer(tracerConfig),
+//	    otel.WithMeter(&otel.MeterConfig{
+//	        ...
+//	        MetricDefs: []*otel.MetricDef{
+//	            {Type: otel.METRIC_TYPE_HISTOGRAM, Name: "asynq_task_processing_latency_ms", Unit: "ms"},
+//	        },
+//	    }),
+//	)
+//	...
+//	mux := asynq.NewServeMux()
+//	mux.Use(otel.AsynqMiddleware(observer, "asynq_task_processing_latency_ms"))
+func AsynqMiddleware(observer IObserver, latencyHistogram MetricName) asynq.MiddlewareFunc {
+	return func(next asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+			startTime := time.Now()
+
+			metricAttrs := map[string]any{
+				"task_type": task.Type(),
+			}
+			spanAttrs := map[string]any{
+				"asynq.task_type": task.Type(),
+			}
+			if queue, ok := asynq.GetQueueName(ctx); ok {
+				metricAttrs["queue"] = queue
+				spanAttrs["asynq.queue"] = queue
+			}
+			if taskID, ok := asynq.GetTaskID(ctx); ok {
+				spanAttrs["asynq.task_id"] = taskID
+			}
+			if retryCount, ok := asynq.GetRetryCount(ctx); ok {
+				spanAttrs["asynq.retry_count"] = retryCount
+			}
+
+			ctx, span := observer.NewSpan(ExtractFromAsynqTask(ctx, task), task.Type())
+			defer span.Done()
+			span.SetAttributes(spanAttrs)
+
+			err := next.ProcessTask(ctx, task)
+
+			metricAttrs["status"] = "success"
+			if err != nil {
+				metricAttrs["status"] = "failed"
+				span.SetError(err)
+			}
+			observer.RecordHistogramWithCtx(ctx, latencyHistogram, float64(time.Since(startTime).Microseconds())/1000, metricAttrs)
+
+			return err
+		})
+	}
+}
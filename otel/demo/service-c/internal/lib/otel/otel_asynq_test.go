@@ -0,0 +1,90 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAsynqMiddlewareContinuesTraceAndRecordsLatency(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tracerProvider.Shutdown(context.Background())
+
+	observer, reader := newTestMeterObserver(t, &MetricDef{Type: METRIC_TYPE_HISTOGRAM, Name: "task_latency"})
+	observer.tracer = tracerProvider.Tracer("test")
+
+	enqueueCtx, enqueueSpan := observer.NewSpan(context.Background(), "enqueue")
+	payload, err := json.Marshal(map[string]any{
+		"trace_carrier": ExportTraceCarrier(enqueueCtx),
+		"payload":       map[string]any{"count": 1},
+	})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	enqueueSpan.Done()
+
+	handlerErr := errors.New("handler failed")
+	var handlerCtx context.Context
+	handler := AsynqMiddleware(observer, "task_latency")(asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		handlerCtx = ctx
+		return handlerErr
+	}))
+
+	if err := handler.ProcessTask(context.Background(), asynq.NewTask("myqueuetask:hello", payload)); !errors.Is(err, handlerErr) {
+		t.Fatalf("ProcessTask error = %v, expected %v", err, handlerErr)
+	}
+
+	AssertTraceContinuity(t, enqueueCtx, handlerCtx)
+
+	var taskSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "myqueuetask:hello" {
+			taskSpan = span
+		}
+	}
+	if taskSpan == nil {
+		t.Fatal("task span not ended")
+	}
+	if taskSpan.Status().Code != codes.Error {
+		t.Errorf("task span status = %v, expected error", taskSpan.Status().Code)
+	}
+
+	histogram := collectMetric(t, reader, "task_latency").Data.(metricdata.Histogram[float64])
+	if len(histogram.DataPoints) != 1 || histogram.DataPoints[0].Count != 1 {
+		t.Fatalf("expected 1 latency sample, got %+v", histogram.DataPoints)
+	}
+	attrs := histogram.DataPoints[0].Attributes
+	if taskType, _ := attrs.Value("task_type"); taskType.AsString() != "myqueuetask:hello" {
+		t.Errorf("task_type = %q, expected %q", taskType.AsString(), "myqueuetask:hello")
+	}
+	if status, _ := attrs.Value("status"); status.AsString() != "failed" {
+		t.Errorf("status = %q, expected %q", status.AsString(), "failed")
+	}
+}
+
+func TestExtractFromAsynqTaskWithoutTraceCarrierKeepsContext(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+	}))
+
+	for _, payload := range [][]byte{nil, []byte("not json"), []byte(`{"count":1}`)} {
+		if got := ExtractFromAsynqTask(ctx, asynq.NewTask("task", payload)); got != ctx {
+			t.Errorf("payload %q: context changed", payload)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newErrorSamplingObserver creates Observer with Tracer sampling by base sampler with error sampling enabled as initTracer does,
+// spans passed on to export are collected by the returned SpanRecorder.
+func newErrorSamplingObserver(t *testing.T, base sdktrace.Sampler) (*Observer, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	set := newErroredTraceSet()
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(&errorSampler{base: base, set: set}),
+		sdktrace.WithSpanProcessor(&errorSamplingProcessor{next: recorder, set: set}),
+	)
+	erroredTraces.Store(set)
+	t.Cleanup(func() {
+		erroredTraces.Store(nil)
+		tracerProvider.Shutdown(context.Background())
+	})
+
+	return &Observer{tracer: tracerProvider.Tracer("test")}, recorder
+}
+
+// exportedSpanNames returns names of spans passed on to export, all of them must be sampled.
+func exportedSpanNames(t *testing.T, recorder *tracetest.SpanRecorder) []string {
+	t.Helper()
+
+	names := []string{}
+	for _, span := range recorder.Ended() {
+		if !span.SpanContext().IsSampled() {
+			t.Errorf("span '%s' is exported unsampled", span.Name())
+		}
+		names = append(names, span.Name())
+	}
+	return names
+}
+
+func TestErrorSamplingKeepsTraceOfErroredChild(t *testing.T) {
+	observer, recorder := newErrorSamplingObserver(t, sdktrace.NeverSample())
+
+	rootCtx, rootSpan := observer.NewSpan(context.Background(), "root")
+	_, childSpan := observer.NewSpan(rootCtx, "child")
+	childSpan.SetError(errors.New("child failed"))
+	childSpan.Done()
+
+	// Started after the error, so sampled by errorSampler
+	_, laterSpan := observer.NewSpan(rootCtx, "later")
+	if !trace.SpanContextFromContext(laterSpan.Context()).IsSampled() {
+		t.Errorf("span started after the error is not sampled")
+	}
+	laterSpan.Done()
+	rootSpan.Done()
+
+	names := exportedSpanNames(t, recorder)
+	expected := []string{"child", "later", "root"}
+	if len(names) != len(expected) {
+		t.Fatalf("exported spans = %v, expected %v", names, expected)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("exported spans = %v, expected %v", names, expected)
+			break
+		}
+	}
+}
+
+func TestErrorSamplingFollowsBaseSamplerForUnerroredTraces(t *testing.T) {
+	t.Run("not sampled", func(t *testing.T) {
+		observer, recorder := newErrorSamplingObserver(t, sdktrace.NeverSample())
+
+		rootCtx, rootSpan := observer.NewSpan(context.Background(), "root")
+		_, childSpan := observer.NewSpan(rootCtx, "child")
+		childSpan.Done()
+		rootSpan.Done()
+
+		if names := exportedSpanNames(t, recorder); len(names) != 0 {
+			t.Errorf("exported spans = %v, expected none", names)
+		}
+	})
+
+	t.Run("sampled", func(t *testing.T) {
+		observer, recorder := newErrorSamplingObserver(t, sdktrace.AlwaysSample())
+
+		rootCtx, rootSpan := observer.NewSpan(context.Background(), "root")
+		_, childSpan := observer.NewSpan(rootCtx, "child")
+		childSpan.Done()
+		rootSpan.Done()
+
+		if names := exportedSpanNames(t, recorder); len(names) != 2 {
+			t.Errorf("exported spans = %v, expected child and root", names)
+		}
+	})
+}
+
+func TestErroredTraceSetPrunesExpiredTraces(t *testing.T) {
+	set := newErroredTraceSet()
+	expiredAt := time.Now().Add(-2 * erroredTraceTTL)
+	for i := range erroredTraceMaxCount {
+		set.traces[traceIDOf(i)] = expiredAt
+	}
+
+	if set.contains(traceIDOf(0)) {
+		t.Errorf("expired trace is still contained")
+	}
+
+	newTraceID := traceIDOf(erroredTraceMaxCount)
+	set.mark(newTraceID)
+	if n := len(set.traces); n != 1 {
+		t.Errorf("remembered traces = %d after pruning, expected 1", n)
+	}
+	if !set.contains(newTraceID) {
+		t.Errorf("trace marked after pruning is not contained")
+	}
+}
+
+func TestErroredTraceSetDropsNewTraceWhenFullOfLiveTraces(t *testing.T) {
+	set := newErroredTraceSet()
+	for i := range erroredTraceMaxCount {
+		set.mark(traceIDOf(i))
+	}
+
+	newTraceID := traceIDOf(erroredTraceMaxCount)
+	set.mark(newTraceID)
+	if n := len(set.traces); n != erroredTraceMaxCount {
+		t.Errorf("remembered traces = %d, expected at most %d", n, erroredTraceMaxCount)
+	}
+	if set.contains(newTraceID) {
+		t.Errorf("trace marked while full of live traces is contained")
+	}
+}
+
+// traceIDOf returns a distinct non-zero TraceID for i.
+func traceIDOf(i int) trace.TraceID {
+	var traceID trace.TraceID
+	traceID[0] = 1
+	traceID[12], traceID[13], traceID[14], traceID[15] = byte(i>>24), byte(i>>16), byte(i>>8), byte(i)
+	return traceID
+}
@@ -2,66 +2,177 @@ package otel
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
 )
 
+// IObserver is the set of Observer features used by services.
+// It allows replacing Observer by NoopObserver (or a mock) in unit tests.
+type IObserver interface {
+	Shutdown()
+
+	NewSpan(ctx context.Context, operation string) (context.Context, *Span)
+	NewSpanWithLinks(ctx context.Context, operation string, links ...trace.Link) (context.Context, *Span)
+
+	InfoLogWithCtx(ctx context.Context, format string, args ...any)
+	WarnLogWithCtx(ctx context.Context, format string, args ...any)
+	DebugLogWithCtx(ctx context.Context, format string, args ...any)
+	ErrorLogWithCtx(ctx context.Context, format string, args ...any)
+	InfoLog(format string, args ...any)
+	WarnLog(format string, args ...any)
+	DebugLog(format string, args ...any)
+	ErrorLog(format string, args ...any)
+	InfoLogKV(ctx context.Context, msg string, attrs map[string]any)
+	WarnLogKV(ctx context.Context, msg string, attrs map[string]any)
+	DebugLogKV(ctx context.Context, msg string, attrs map[string]any)
+	ErrorLogKV(ctx context.Context, msg string, attrs map[string]any)
+	WithLogGroup(name string) IObserver
+
+	UnregisterMetric(name MetricName) error
+	RecordCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any)
+	RecordUpDownCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any)
+	RecordHistogramWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any)
+	RecordGaugeWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any)
+	RecordIntGaugeWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any)
+	RecordCounter(name MetricName, value int64, metricAttrs map[string]any)
+	RecordUpDownCounter(name MetricName, value int64, metricAttrs map[string]any)
+	RecordHistogram(name MetricName, value float64, metricAttrs map[string]any)
+	RecordGauge(name MetricName, value float64, metricAttrs map[string]any)
+	RecordIntGauge(name MetricName, value int64, metricAttrs map[string]any)
+	RecordCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue)
+	RecordUpDownCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue)
+	RecordHistogramAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue)
+	RecordGaugeAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue)
+	RecordIntGaugeAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue)
+	HistogramSnapshot(name MetricName) (int64, float64)
+
+	GetCacheTraceCarrierFromGroup(group string, key string) (TraceCarrier, error)
+	SetCacheTraceCarrierFromGroup(group string, key string, traceCarrier TraceCarrier) error
+	DeleteCacheTraceCarrierFromGroup(group string, key string) error
+	DeleteCacheTraceCarrierGroup(group string) error
+	DeleteCacheTraceCarrierGroupAndNotify(group string, channel string) error
+	ClearCacheTraceCarrier() error
+	ClearCacheTraceCarrierOlderThan(age time.Duration) (int, error)
+	ListCacheTraceCarrierKeysInGroup(group string) ([]string, error)
+	CountCacheTraceCarrierGroup(group string) (int64, error)
+
+	HealthCheck(ctx context.Context) error
+	DiagnosticsSnapshot(ctx context.Context) Diagnostics
+}
+
+var _ IObserver = (*Observer)(nil)
+
 // Observer manages lifecycle of all OpenTelemetry components.
 type Observer struct {
 	// Main feature
 
 	tracer                 trace.Tracer            // Tracer instance for creating tracing spans
 	logger                 *slog.Logger            // Logger instance for structured logging
+	logCallerSkip          int                     // Additional stack frames to skip for "meta" field of logs
+	logFullPath            bool                    // Use full source path for "meta" field of logs
 	meter                  metric.Meter            // Meter instance for collecting metrics
 	metricCollectorManager *metricCollectorManager // Metric collector manager for all registered metric
 
 	// Other feature
 
-	cache Cache // Cache for storing Trace Carriers (trace context)
+	cache         Cache         // Cache for storing Trace Carriers (trace context)
+	exportHealth  *exportHealth // Health of telemetry pipeline, reported by custom_otel_export_* metrics
+	otlpEndPoints []string      // OTLP endpoints of Tracer and Logger, checked by HealthCheck (Meter endpoint is in meterConfig)
+
+	// Pending config, initialized after all options are applied
+
+	meterConfig    *MeterConfig // Meter config, initialized last so additional metric readers can be attached
+	prometheusAddr string       // Address serving Prometheus scrape endpoint (empty if disabled)
 
-	shutdowns []func(context.Context) // List of shutdown functions for cleanup
+	shutdowns []shutdownFunc // List of shutdown functions for cleanup
+}
+
+// signalKind tags a shutdown function by its telemetry signal, Shutdown runs them in order of signalKind.
+type signalKind int
+
+const (
+	// Meter and Logger are flushed first, their buffered data may reference spans of Tracer
+	signalKindMeter signalKind = iota
+	signalKindLogger
+	signalKindTracer
+)
+
+// shutdownFunc is a shutdown function tagged by its telemetry signal.
+type shutdownFunc struct {
+	kind     signalKind
+	shutdown func(context.Context)
 }
 
 // Shutdown flushes all pending telemetry data and cleans up resources.
+// Meter and Logger are shut down before Tracer, regardless of the order of options.
 // It should be called before application exit.
 func (o *Observer) Shutdown() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	for _, shutdown := range o.shutdowns {
-		shutdown(shutdownCtx)
+	shutdowns := slices.Clone(o.shutdowns)
+	slices.SortStableFunc(shutdowns, func(a, b shutdownFunc) int {
+		return int(a.kind) - int(b.kind)
+	})
+
+	for _, fn := range shutdowns {
+		fn.shutdown(shutdownCtx)
 	}
 }
 
 // ObserverOption configures the Otel Observer during initialization.
 type ObserverOption interface {
-	apply(obsv *Observer)
+	apply(obsv *Observer) error
 }
 
 // observerOptionFunc implements ObserverOption using a function.
-type observerOptionFunc func(*Observer)
+type observerOptionFunc func(*Observer) error
 
-func (obsvOptFunc observerOptionFunc) apply(obsv *Observer) {
-	obsvOptFunc(obsv)
+func (obsvOptFunc observerOptionFunc) apply(obsv *Observer) error {
+	return obsvOptFunc(obsv)
 }
 
 // WithTracer enables distributed tracing with the given configuration.
 // Returns nil if config is nil.
 // This is mandatory if using Tracer; otherwise, it will no effect if Tracer is used without configuring it when initializing Otel Observer.
-func WithTracer(config *TracerConfig) ObserverOption {
-	return observerOptionFunc(func(o *Observer) {
+// Tracer can be further customized by opts (e.g. WithErrorSampling()).
+func WithTracer(config *TracerConfig, opts ...TracerOption) ObserverOption {
+	return observerOptionFunc(func(o *Observer) error {
 		if config == nil {
-			return
+			return nil
 		}
 
-		tracer, shutdown := initTracer(config)
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid Tracer config: %v", err)
+		}
+
+		tracerOpts := tracerOptions{}
+		for _, opt := range opts {
+			opt(&tracerOpts)
+		}
+
+		tracer, shutdown, err := initTracer(config, tracerOpts, o.exportHealth)
+		if err != nil {
+			return err
+		}
 
 		o.tracer = tracer
-		o.shutdowns = append(o.shutdowns, shutdown)
+		o.otlpEndPoints = append(o.otlpEndPoints, config.EndPoint)
+		o.shutdowns = append(o.shutdowns, shutdownFunc{kind: signalKindTracer, shutdown: shutdown})
+		return nil
 	})
 }
 
@@ -70,15 +181,26 @@ func WithTracer(config *TracerConfig) ObserverOption {
 // Returns nil if config is nil.
 // This is mandatory if using Logger; otherwise, it will no effect if Logger is used without configuring it when initializing Otel Observer.
 func WithLogger(config *LoggerConfig) ObserverOption {
-	return observerOptionFunc(func(o *Observer) {
+	return observerOptionFunc(func(o *Observer) error {
 		if config == nil {
-			return
+			return nil
 		}
 
-		logger, shutdown := initLogger(config)
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid Logger config: %v", err)
+		}
+
+		logger, shutdown, err := initLogger(config, o.exportHealth)
+		if err != nil {
+			return err
+		}
 
 		o.logger = logger
-		o.shutdowns = append(o.shutdowns, shutdown)
+		o.logCallerSkip = config.CallerSkip
+		o.logFullPath = config.LocalLogFullPath
+		o.otlpEndPoints = append(o.otlpEndPoints, config.EndPoint)
+		o.shutdowns = append(o.shutdowns, shutdownFunc{kind: signalKindLogger, shutdown: shutdown})
+		return nil
 	})
 }
 
@@ -87,79 +209,132 @@ func WithLogger(config *LoggerConfig) ObserverOption {
 // Returns nil if config is nil.
 // This is mandatory if using Meter; otherwise, it will no effect if Meter is used without configuring it when initializing Otel Observer.
 func WithMeter(config *MeterConfig) ObserverOption {
-	return observerOptionFunc(func(o *Observer) {
+	return observerOptionFunc(func(o *Observer) error {
 		if config == nil {
-			return
+			return nil
+		}
+
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid Meter config: %v", err)
 		}
 
 		if config.MetricCollectionInterval <= 0 {
 			config.MetricCollectionInterval = defaultMeterInterval
 		}
 
-		meter, metricCollectorManager, shutdown := initMeter(config)
-
-		o.meter = meter
-		o.metricCollectorManager = metricCollectorManager
-		o.shutdowns = append(o.shutdowns, shutdown)
+		// Meter is initialized after all options are applied, see initPendingMeter
+		o.meterConfig = config
+		return nil
 	})
 }
 
 // WithRedisCache enables Redis-based trace context storage for async operations.
 // Useful for propagating trace context across message queues or job systems.
 // Returns nil if config is nil.
-// This is mandatory if using Cache; otherwise, it will crash if Cache is used without configuring it when initializing Otel Observer.
+// This is mandatory if using Cache; otherwise, Cache functions return ErrCacheUnconfigured.
 func WithRedisCache(config *RedisConfig) ObserverOption {
-	return observerOptionFunc(func(o *Observer) {
+	return observerOptionFunc(func(o *Observer) error {
 		if config == nil {
-			return
+			return nil
 		}
 
-		if config.PoolSize <= 0 {
-			config.PoolSize = defaultRedisPoolSize
-		}
-		if config.PoolTimeoutSec <= 0 {
-			config.PoolTimeoutSec = defaultRedisPoolTimeoutSec
-		}
-		if config.IdleTimeoutSec <= 0 {
-			config.IdleTimeoutSec = defaultRedisIdleTimeoutSec
-		}
-		if config.ReadTimeoutSec <= 0 {
-			config.ReadTimeoutSec = defaultRedisReadTimeoutSec
-		}
-		if config.WriteTimeoutSec <= 0 {
-			config.WriteTimeoutSec = defaultRedisWriteTimeoutSec
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("invalid Redis config: %v", err)
 		}
 
-		redisCache := initRedisCache(config)
+		config.setDefaults()
+
+		redisCache, err := initRedisCache(config)
+		if err != nil {
+			return err
+		}
 
 		o.cache = redisCache
+		return nil
 	})
 }
 
 // init sets some configs for OpenTelemetry.
 func init() {
-	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(cause error) {
-		stdLog.Printf("[error] Error occurred: %v", cause)
-	}))
+	otel.SetErrorHandler(defaultErrorHandler)
+}
+
+// defaultErrorHandler logs errors of OpenTelemetry before any Otel Observer is initialized.
+var defaultErrorHandler = otel.ErrorHandlerFunc(func(cause error) {
+	stdLog.Printf("[error] Error occurred: %v", cause)
+})
+
+// ResetObserver shuts down the given Otel Observer (if not nil) and resets package-level and global OpenTelemetry state
+// (providers, propagator, error handler, error sampling, log level and default Logger), so the next NewOtelObserver
+// starts from a clean state. Providers still set globally by other Otel Observers are shut down too.
+// It is intended for tests only, services should call Shutdown once before exit.
+//
+// Example:
+//
+//	observer := otel.MustNewOtelObserver(...)
+//	t.Cleanup(func() { otel.ResetObserver(observer) })
+func ResetObserver(o *Observer) {
+	if o != nil {
+		o.Shutdown()
+		*o = Observer{}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// Shutting down an already shut down provider is a no-op, its error is ignored
+	if tracerProvider, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); ok {
+		_ = tracerProvider.Shutdown(shutdownCtx)
+	}
+	if meterProvider, ok := otel.GetMeterProvider().(*sdkmetric.MeterProvider); ok {
+		_ = meterProvider.Shutdown(shutdownCtx)
+	}
+
+	otel.SetTracerProvider(tracenoop.NewTracerProvider())
+	otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	otel.SetErrorHandler(defaultErrorHandler)
+
+	erroredTraces.Store(nil)
+	logLevelVar.Set(slog.LevelInfo)
+	defaultLogger = newDefaultLogger()
 }
 
 // NewOtelObserver initializes Otel Observer (OpenTelemetry Observer) with the given options.
-// Returns a *Observer.
+// Returns a *Observer, or an error if any option has invalid config or its component fails to initialize.
+// Components initialized before the failure are shut down.
 //
 // Example:
 //
-//	observer := otel.NewOtelObserver(
+//	observer, err := otel.NewOtelObserver(
 //	    otel.WithTracer(&otel.TracerConfig{...}),
 //	    otel.WithLogger(&otel.LoggerConfig{...}),
 //	)
-//	defer observer.shutdown()
-func NewOtelObserver(opts ...ObserverOption) *Observer {
+//	if err != nil {
+//	    ...
+//	}
+//	defer observer.Shutdown()
+func NewOtelObserver(opts ...ObserverOption) (*Observer, error) {
 	obsv := &Observer{
-		shutdowns: make([]func(context.Context), 0),
+		exportHealth: newExportHealth(),
+		shutdowns:    make([]shutdownFunc, 0),
 	}
 
+	// Error handler is global, errors are counted by the latest Otel Observer
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(cause error) {
+		stdLog.Printf("[error] Error occurred: %v", cause)
+		obsv.exportHealth.recordError()
+	}))
+
 	for _, opt := range opts {
-		opt.apply(obsv)
+		if err := opt.apply(obsv); err != nil {
+			obsv.Shutdown()
+			return nil, err
+		}
+	}
+
+	if err := obsv.initPendingMeter(); err != nil {
+		obsv.Shutdown()
+		return nil, err
 	}
 
 	if obsv.tracer == nil {
@@ -167,5 +342,56 @@ func NewOtelObserver(opts ...ObserverOption) *Observer {
 		stdLog.Printf("[warning] Tracer is unconfigured, using the default alternative Tracer")
 	}
 
+	if obsv.logger == nil {
+		obsv.logger = defaultLogger
+		stdLog.Printf("[warning] Logger is unconfigured, using the default alternative Logger (stdout only): %v", ErrLoggerUnconfigured)
+	}
+
+	return obsv, nil
+}
+
+// initPendingMeter initializes Meter configured by WithMeter, with Prometheus reader if WithPrometheusExporter is used.
+func (o *Observer) initPendingMeter() error {
+	if o.meterConfig == nil {
+		if o.prometheusAddr != "" {
+			return errors.New("Prometheus exporter requires Meter to be configured")
+		}
+		return nil
+	}
+
+	readers := make([]sdkmetric.Reader, 0)
+	if o.prometheusAddr != "" {
+		reader, shutdown, err := initPrometheusExporter(o.prometheusAddr)
+		if err != nil {
+			return err
+		}
+		o.shutdowns = append(o.shutdowns, shutdownFunc{kind: signalKindMeter, shutdown: shutdown})
+		readers = append(readers, reader)
+	}
+
+	meter, metricCollectorManager, shutdown, err := initMeter(o.meterConfig, o.exportHealth, readers...)
+	if err != nil {
+		return err
+	}
+
+	o.meter = meter
+	o.metricCollectorManager = metricCollectorManager
+	o.shutdowns = append(o.shutdowns, shutdownFunc{kind: signalKindMeter, shutdown: shutdown})
+	return nil
+}
+
+// MustNewOtelObserver is like NewOtelObserver but panics if Otel Observer fails to initialize.
+//
+// Example:
+//
+//	observer := otel.MustNewOtelObserver(
+//	    otel.WithTracer(&otel.TracerConfig{...}),
+//	)
+//	defer observer.Shutdown()
+func MustNewOtelObserver(opts ...ObserverOption) *Observer {
+	obsv, err := NewOtelObserver(opts...)
+	if err != nil {
+		panic(fmt.Sprintf("failed to initialize Otel Observer: %v", err))
+	}
 	return obsv
 }
@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowedHistogramSnapshotPercentiles(t *testing.T) {
+	histogram := NewWindowedHistogram("latency", time.Minute, 0)
+	for value := 1; value <= 101; value++ {
+		histogram.Record(float64(value))
+	}
+
+	p50, p95, p99, count := histogram.Snapshot()
+	if p50 != 51 || p95 != 96 || p99 != 100 || count != 101 {
+		t.Errorf("Snapshot() = (%v, %v, %v, %v), expected (51, 96, 100, 101)", p50, p95, p99, count)
+	}
+}
+
+func TestWindowedHistogramExpiresSamplesOutOfWindow(t *testing.T) {
+	histogram := NewWindowedHistogram("latency", 50*time.Millisecond, 0)
+	histogram.Record(1000)
+	time.Sleep(100 * time.Millisecond)
+	histogram.Record(10)
+
+	p50, _, p99, count := histogram.Snapshot()
+	if count != 1 || p50 != 10 || p99 != 10 {
+		t.Errorf("Snapshot() = (p50 %v, p99 %v, count %v), expected only the sample in window", p50, p99, count)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if _, _, _, count := histogram.Snapshot(); count != 0 {
+		t.Errorf("count = %d after window passed, expected 0", count)
+	}
+}
+
+func TestWindowedHistogramKeepsMostRecentMaxSamples(t *testing.T) {
+	histogram := NewWindowedHistogram("latency", time.Minute, 10)
+	for value := 1; value <= 25; value++ {
+		histogram.Record(float64(value))
+	}
+
+	p50, _, _, count := histogram.Snapshot()
+	if count != 10 {
+		t.Fatalf("count = %d, expected max samples 10", count)
+	}
+	// Samples 16..25 are kept
+	if p50 != 20.5 {
+		t.Errorf("p50 = %v, expected 20.5", p50)
+	}
+}
+
+func TestWindowedHistogramReset(t *testing.T) {
+	histogram := NewWindowedHistogram("latency", time.Minute, 10)
+	for value := 1; value <= 15; value++ {
+		histogram.Record(float64(value))
+	}
+	histogram.Reset()
+
+	if p50, p95, p99, count := histogram.Snapshot(); p50 != 0 || p95 != 0 || p99 != 0 || count != 0 {
+		t.Errorf("Snapshot() = (%v, %v, %v, %v) after Reset, expected zeros", p50, p95, p99, count)
+	}
+
+	histogram.Record(42)
+	if p50, _, _, count := histogram.Snapshot(); p50 != 42 || count != 1 {
+		t.Errorf("Snapshot() = (p50 %v, count %v) after Reset and Record, expected (42, 1)", p50, count)
+	}
+}
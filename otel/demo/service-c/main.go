@@ -1,6 +1,10 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
 	"thanhldt060802/common/pubsub"
 	"thanhldt060802/internal"
 	"thanhldt060802/internal/lib/otel"
@@ -10,6 +14,7 @@ import (
 	"thanhldt060802/repository"
 	"thanhldt060802/repository/db"
 	"thanhldt060802/service"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -38,9 +43,9 @@ func init() {
 		Database: viper.GetInt("redis.database"),
 		Password: viper.GetString("redis.password"),
 	})
-	pubsub.RedisSubInstance = pubsub.NewRedisSub[*model.ExamplePubSubMessage](redisclient.RedisClientConnInstance.GetClient())
+	pubsub.RedisSubInstance = pubsub.NewRedisSub[model.ExamplePubSubMessage](redisclient.RedisClientConnInstance.GetClient(), pubsub.WithDeadLetter("", 3), pubsub.WithConcurrency("otel.pubsub.testing", 4))
 
-	internal.Observer = otel.NewOtelObserver(
+	internal.Observer = otel.MustNewOtelObserver(
 		otel.WithTracer(&otel.TracerConfig{
 			ServiceName:    viper.GetString("app.name"),
 			ServiceVersion: viper.GetString("app.version"),
@@ -49,6 +54,7 @@ func init() {
 			HttpHeader: map[string]string{
 				"Authorization": "Bearer " + viper.GetString("observer.tracer.bearer_token"),
 			},
+			SampleRatio: viper.GetFloat64("observer.tracer.sample_ratio"),
 		}),
 		otel.WithLogger(&otel.LoggerConfig{
 			ServiceName:    viper.GetString("app.name"),
@@ -58,8 +64,9 @@ func init() {
 			HttpHeader: map[string]string{
 				"Authorization": "Bearer " + viper.GetString("observer.logger.bearer_token"),
 			},
-			LocalLogFile:  viper.GetString("observer.logger.local_log_file"),
-			LocalLogLevel: otel.LogLevel(viper.GetString("observer.logger.local_log_level")),
+			LocalLogFile:   viper.GetString("observer.logger.local_log_file"),
+			LocalLogLevel:  otel.LogLevel(viper.GetString("observer.logger.local_log_level")),
+			LocalLogFormat: otel.LogFormat(viper.GetString("observer.logger.local_log_format")),
 		}),
 	)
 }
@@ -69,11 +76,19 @@ func main() {
 
 	initRepository()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	exampleService := service.NewExampleService()
-	exampleService.InitSubscriber()
+	subscriber := exampleService.InitSubscriber(ctx)
 
 	log.Infof("Ready to consume message")
-	select {}
+	<-ctx.Done()
+
+	log.Infof("Shutting down subscriber")
+	if err := subscriber.Wait(10 * time.Second); err != nil {
+		log.Errorf("Stop subscriber failed: %v", err)
+	}
 }
 
 func initRepository() {
@@ -11,7 +11,7 @@ import (
 
 type (
 	IExampleService interface {
-		InitSubscriber()
+		InitSubscriber(ctx context.Context) *pubsub.SubscriberHandle
 	}
 	ExampleService struct {
 	}
@@ -21,8 +21,9 @@ func NewExampleService() IExampleService {
 	return &ExampleService{}
 }
 
-func (s *ExampleService) InitSubscriber() {
-	pubsub.RedisSubInstance.Subscribe(context.Background(), "otel.pubsub.testing", func(message *model.ExamplePubSubMessage) {
+// InitSubscriber registers handlers and starts consuming messages until ctx is done.
+func (s *ExampleService) InitSubscriber(ctx context.Context) *pubsub.SubscriberHandle {
+	err := pubsub.RedisSubInstance.Subscribe("otel.pubsub.testing", func(ctx context.Context, message *model.ExamplePubSubMessage) error {
 		subCtx, span := internal.Observer.NewSpan(message.ExtractContext(), "SubscribeMessage")
 		defer span.Done()
 
@@ -34,7 +35,7 @@ func (s *ExampleService) InitSubscriber() {
 		example, err := repository.ExampleRepo.GetById(subCtx, message.ExampleUuid)
 		if err != nil {
 			span.SetError(err)
-			return
+			return err
 		}
 
 		if example == nil {
@@ -42,5 +43,12 @@ func (s *ExampleService) InitSubscriber() {
 		} else {
 			fmt.Println(*example)
 		}
+
+		return nil
 	})
+	if err != nil {
+		internal.Observer.ErrorLog("Subscribe channel 'otel.pubsub.testing' failed: %v", err)
+	}
+
+	return pubsub.RedisSubInstance.Start(ctx)
 }
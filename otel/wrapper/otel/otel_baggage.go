@@ -0,0 +1,55 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// SetBaggage returns a copy of ctx with the baggage member key=value.
+// Baggage is propagated to downstream services along with trace context, and written to logs as "baggage" group.
+// Invalid key or value is ignored and ctx is returned unchanged.
+//
+// Example:
+//
+//	ctx = otel.SetBaggage(ctx, "tenant_id", tenantID)
+func SetBaggage(ctx context.Context, key string, value string) context.Context {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		stdLog.Printf("[warning] Invalid baggage member '%s': %v", key, err)
+		return ctx
+	}
+
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		stdLog.Printf("[warning] Failed to set baggage member '%s': %v", key, err)
+		return ctx
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// GetBaggage returns value of the baggage member key in ctx, or empty string if not found.
+//
+// Example:
+//
+//	tenantID := otel.GetBaggage(ctx, "tenant_id")
+func GetBaggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// getBaggageAttrs returns baggage members in ctx as log attributes (sorted by key).
+func getBaggageAttrs(ctx context.Context) []any {
+	members := baggage.FromContext(ctx).Members()
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Key() < members[j].Key()
+	})
+
+	attrs := make([]any, len(members))
+	for i, member := range members {
+		attrs[i] = slog.String(member.Key(), member.Value())
+	}
+	return attrs
+}
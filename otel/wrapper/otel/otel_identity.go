@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Identity attribute keys, added to logs and spans automatically and to metrics opting in by MetricDef.IdentityAttrs.
+const (
+	IDENTITY_ATTR_TENANT_ID = "tenant_id"
+	IDENTITY_ATTR_USER_ID   = "user_id"
+)
+
+// identityCtxKey is the context key of identity.
+type identityCtxKey struct{}
+
+// identity is the tenant and user of the current request.
+type identity struct {
+	tenantID string
+	userID   string
+}
+
+// WithIdentity returns a copy of ctx containing tenant and user of the current request.
+// Logs written with this context include tenant_id and user_id fields, spans created from it have them as attributes,
+// and metrics listing them in MetricDef.IdentityAttrs record them as attributes. Empty values are omitted.
+//
+// Example:
+//
+//	ctx = otel.WithIdentity(ctx, claims.TenantID, claims.UserID)
+//	observer.InfoLogWithCtx(ctx, "Order created") // includes tenant_id and user_id
+func WithIdentity(ctx context.Context, tenantID string, userID string) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, identity{tenantID: tenantID, userID: userID})
+}
+
+// GetIdentity returns tenant and user in ctx set by WithIdentity, empty strings if not found.
+func GetIdentity(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	id, _ := ctx.Value(identityCtxKey{}).(identity)
+	return id.tenantID, id.userID
+}
+
+// getIdentityAttrs returns non-empty identity values in ctx as attributes.
+func getIdentityAttrs(ctx context.Context) []attribute.KeyValue {
+	tenantID, userID := GetIdentity(ctx)
+
+	attrs := make([]attribute.KeyValue, 0, 2)
+	if tenantID != "" {
+		attrs = append(attrs, attribute.String(IDENTITY_ATTR_TENANT_ID, tenantID))
+	}
+	if userID != "" {
+		attrs = append(attrs, attribute.String(IDENTITY_ATTR_USER_ID, userID))
+	}
+	return attrs
+}
+
+// getIdentityLogAttrs returns non-empty identity values in ctx as log attributes.
+func getIdentityLogAttrs(ctx context.Context) []slog.Attr {
+	attrs := getIdentityAttrs(ctx)
+
+	logAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		logAttrs[i] = slog.String(string(attr.Key), attr.Value.AsString())
+	}
+	return logAttrs
+}
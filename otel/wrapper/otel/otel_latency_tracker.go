@@ -0,0 +1,91 @@
+package otel
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Default number of recent samples kept by LatencyTracker.
+const defaultLatencyTrackerReservoirSize = 1024
+
+// LatencyTracker wraps a registered histogram, it forwards recorded values to the histogram
+// and keeps a bounded reservoir of recent samples for computing percentiles locally (e.g. for a quick log line).
+type LatencyTracker struct {
+	observer IObserver
+	name     MetricName
+
+	samples []float64 // Ring buffer of recent samples
+	next    int       // Index of the next sample in ring buffer
+	count   int       // Number of samples in ring buffer
+	mu      sync.Mutex
+}
+
+// NewLatencyTracker creates a LatencyTracker for the histogram with the given name.
+// reservoirSize is the number of recent samples kept (<= 0: 1024).
+//
+// Example:
+//
+//	tracker := otel.NewLatencyTracker(observer, "request_latency", 1000)
+//	tracker.Record(ctx, 123.45, map[string]any{"endpoint": "/api/users"})
+//	observer.InfoLog("p50=%v p95=%v p99=%v", tracker.Percentile(50), tracker.Percentile(95), tracker.Percentile(99))
+func NewLatencyTracker(observer IObserver, name MetricName, reservoirSize int) *LatencyTracker {
+	if reservoirSize <= 0 {
+		reservoirSize = defaultLatencyTrackerReservoirSize
+	}
+
+	return &LatencyTracker{
+		observer: observer,
+		name:     name,
+		samples:  make([]float64, reservoirSize),
+	}
+}
+
+// Record keeps the value in reservoir and records it in the histogram (callback: RecordHistogramWithCtx).
+func (lt *LatencyTracker) Record(ctx context.Context, value float64, metricAttrs map[string]any) {
+	lt.mu.Lock()
+	lt.samples[lt.next] = value
+	lt.next = (lt.next + 1) % len(lt.samples)
+	if lt.count < len(lt.samples) {
+		lt.count++
+	}
+	lt.mu.Unlock()
+
+	lt.observer.RecordHistogramWithCtx(ctx, lt.name, value, metricAttrs)
+}
+
+// Percentile returns the p-th percentile (p in [0, 100]) of samples in reservoir, using linear interpolation between closest ranks.
+// Returns 0 if reservoir is empty.
+func (lt *LatencyTracker) Percentile(p float64) float64 {
+	lt.mu.Lock()
+	sorted := make([]float64, lt.count)
+	copy(sorted, lt.samples[:lt.count])
+	lt.mu.Unlock()
+
+	sort.Float64s(sorted)
+	return percentileOfSorted(sorted, p)
+}
+
+// percentileOfSorted returns the p-th percentile (p in [0, 100]) of sorted samples, using linear interpolation between closest ranks.
+// Returns 0 if there is no sample.
+func percentileOfSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Reset drops all samples in reservoir, the histogram is not affected.
+func (lt *LatencyTracker) Reset() {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	lt.next = 0
+	lt.count = 0
+}
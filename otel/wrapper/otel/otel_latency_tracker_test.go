@@ -0,0 +1,63 @@
+package otel
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLatencyTrackerPercentile(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		tracker := NewLatencyTracker(NewNoopObserver(), "latency", 0)
+
+		for _, p := range []float64{0, 50, 100} {
+			if got := tracker.Percentile(p); got != 0 {
+				t.Errorf("Percentile(%v) = %v, expected 0", p, got)
+			}
+		}
+	})
+
+	t.Run("single sample", func(t *testing.T) {
+		tracker := NewLatencyTracker(NewNoopObserver(), "latency", 0)
+		tracker.Record(context.Background(), 42, nil)
+
+		for _, p := range []float64{0, 50, 99, 100} {
+			if got := tracker.Percentile(p); got != 42 {
+				t.Errorf("Percentile(%v) = %v, expected 42", p, got)
+			}
+		}
+	})
+
+	t.Run("known inputs", func(t *testing.T) {
+		tracker := NewLatencyTracker(NewNoopObserver(), "latency", 0)
+		// Recorded out of order, Percentile sorts samples
+		for _, value := range []float64{40, 10, 30, 20, 50} {
+			tracker.Record(context.Background(), value, nil)
+		}
+
+		cases := map[float64]float64{
+			-10: 10, // Clamped to 0
+			0:   10,
+			25:  20,
+			50:  30,
+			90:  46, // Interpolated between 40 and 50
+			100: 50,
+			200: 50, // Clamped to 100
+		}
+		for p, expected := range cases {
+			if got := tracker.Percentile(p); got != expected {
+				t.Errorf("Percentile(%v) = %v, expected %v", p, got, expected)
+			}
+		}
+	})
+
+	t.Run("only recent samples", func(t *testing.T) {
+		tracker := NewLatencyTracker(NewNoopObserver(), "latency", 3)
+		for _, value := range []float64{1000, 2000, 1, 2, 3} {
+			tracker.Record(context.Background(), value, nil)
+		}
+
+		if got := tracker.Percentile(100); got != 3 {
+			t.Errorf("Percentile(100) = %v, expected 3 after older samples are overwritten", got)
+		}
+	})
+}
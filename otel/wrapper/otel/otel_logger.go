@@ -9,14 +9,15 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/contrib/bridges/otelslog"
-	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/sdk/log"
-	"go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Error definitions for Logger.
@@ -40,52 +41,180 @@ const (
 	LOG_LEVEL_ERROR LogLevel = "error"
 )
 
+// LogFormat defines the output format of local logging.
+type LogFormat string
+
+// Log format definitions for local logging.
+const (
+	// LOG_FORMAT_JSON is used for structured JSON logs (e.g. production).
+	LOG_FORMAT_JSON LogFormat = "json"
+	// LOG_FORMAT_TEXT is used for human-readable key=value logs (e.g. local development).
+	LOG_FORMAT_TEXT LogFormat = "text"
+)
+
+// logLevelVar is the current log level of Logger, shared by all handlers.
+var logLevelVar slog.LevelVar
+
+// SetLogLevel changes log level of Logger at runtime.
+// Unknown log level falls back to LOG_LEVEL_INFO.
+//
+// Example:
+//
+//	otel.SetLogLevel(otel.LOG_LEVEL_DEBUG)
+func SetLogLevel(level LogLevel) {
+	logLevelVar.Set(toSlogLevel(level))
+}
+
+// toSlogLevel converts LogLevel to slog.Level.
+func toSlogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LOG_LEVEL_INFO:
+		{
+			return slog.LevelInfo
+		}
+	case LOG_LEVEL_WARN:
+		{
+			return slog.LevelWarn
+		}
+	case LOG_LEVEL_DEBUG:
+		{
+			return slog.LevelDebug
+		}
+	case LOG_LEVEL_ERROR:
+		{
+			return slog.LevelError
+		}
+	default:
+		{
+			return slog.LevelInfo
+		}
+	}
+}
+
 // LoggerConfig configures structured logging with OpenTelemetry integration.
 type LoggerConfig struct {
 	ServiceName    string            // Name of the service
 	ServiceVersion string            // Version of the service
 	EndPoint       string            // OTLP endpoint for exporting log data
 	Insecure       bool              // Allow HTTP schema, instead of HTTPS
-	HttpHeader     map[string]string // Additional HTTP headers
+	HttpHeader     map[string]string // Additional HTTP headers (gRPC metadata when using gRPC protocol)
+	Protocol       ExportProtocol    // OTLP protocol for exporting (default: EXPORT_PROTOCOL_HTTP)
+
+	ResourceAttributes map[string]string // Custom resource attributes added to all exported data, e.g. "deployment.environment", "k8s.pod.name"
+
+	LocalLogFile   string    // Path to local log file
+	LocalLogLevel  LogLevel  // Log level for local file logging
+	LocalLogFormat LogFormat // Output format of local logging, OTLP logging is unaffected (default: LOG_FORMAT_JSON)
+	DisableStdout  bool      // Don't write local logs to stdout, e.g. in containers exporting logs to collector only
+
+	CallerSkip       int  // Number of additional stack frames to skip for "meta" field, used when log functions are wrapped (0: direct call site)
+	LocalLogFullPath bool // Use full source path instead of file name for "meta" field
+
+	MaxSizeMB  int // Max size in megabytes of local log file before rotating (0: 100MB when rotation is enabled)
+	MaxBackups int // Max number of rotated local log files to retain (0: retain all)
+	MaxAgeDays int // Max number of days to retain rotated local log files (0: no age limit)
+
+	RedactKeys []string // Attribute keys whose values are redacted, case-insensitive (empty: defaultLogRedactKeys)
+
+	MaxQueueSize       int           // Max number of log records buffered for OTLP export, records are dropped when full (0: SDK default 2048)
+	BatchTimeout       time.Duration // Max delay before exporting buffered log records (0: SDK default 1s)
+	MaxExportBatchSize int           // Max number of log records per export (0: SDK default 512)
+}
+
+// Default Logger settings.
+var (
+	// defaultLogRedactKeys is list of attribute keys containing sensitive data.
+	defaultLogRedactKeys = []string{"authorization", "password", "token"}
+)
+
+// Replacement value for redacted attributes.
+const logRedactedValue = "***"
+
+// defaultLogger writes logs to stdout only, used when Logger is unconfigured to avoid losing logs.
+var defaultLogger = newDefaultLogger()
+
+func newDefaultLogger() *slog.Logger {
+	return slog.New(newMultiHandler(defaultLogRedactKeys, slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: &logLevelVar})))
+}
 
-	LocalLogFile  string   // Path to local log file
-	LocalLogLevel LogLevel // Log level for local file logging
+// isRotationEnabled reports whether local log file rotation is configured.
+func (config *LoggerConfig) isRotationEnabled() bool {
+	return config.MaxSizeMB > 0 || config.MaxBackups > 0 || config.MaxAgeDays > 0
+}
+
+// Validate checks required fields and values of LoggerConfig.
+func (config *LoggerConfig) Validate() error {
+	if config.ServiceName == "" {
+		return errors.New("service name is required")
+	}
+	if config.EndPoint == "" {
+		return errors.New("end point is required")
+	}
+	if err := config.Protocol.validate(); err != nil {
+		return err
+	}
+	if err := validateResourceAttrs(config.ResourceAttributes); err != nil {
+		return err
+	}
+
+	switch config.LocalLogLevel {
+	case "", LOG_LEVEL_INFO, LOG_LEVEL_WARN, LOG_LEVEL_DEBUG, LOG_LEVEL_ERROR:
+	default:
+		return fmt.Errorf("local log level '%s' is not valid", config.LocalLogLevel)
+	}
+
+	switch config.LocalLogFormat {
+	case "", LOG_FORMAT_JSON, LOG_FORMAT_TEXT:
+	default:
+		return fmt.Errorf("local log format '%s' is not valid", config.LocalLogFormat)
+	}
+
+	if config.MaxSizeMB < 0 || config.MaxBackups < 0 || config.MaxAgeDays < 0 {
+		return errors.New("log rotation settings must be non-negative")
+	}
+
+	if config.CallerSkip < 0 {
+		return fmt.Errorf("caller skip %d must be non-negative", config.CallerSkip)
+	}
+
+	if config.MaxQueueSize < 0 || config.BatchTimeout < 0 || config.MaxExportBatchSize < 0 {
+		return errors.New("batch processor settings must be non-negative")
+	}
+	if config.MaxQueueSize > 0 && config.MaxExportBatchSize > config.MaxQueueSize {
+		return fmt.Errorf("max export batch size %d must not exceed max queue size %d", config.MaxExportBatchSize, config.MaxQueueSize)
+	}
+	return nil
 }
 
 // initLogger initializes the Logger, returns Logger and a cleanup function.
 // Logs are sent to both OTLP endpoint and local output (stdout + optional file).
 // Each log entry includes trace_id and span_id for correlation with traces.
-func initLogger(config *LoggerConfig) (*slog.Logger, func(ctx context.Context)) {
+func initLogger(config *LoggerConfig, health *exportHealth) (*slog.Logger, func(ctx context.Context), error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	opts := []otlploghttp.Option{
-		otlploghttp.WithEndpoint(config.EndPoint),
-	}
-	if config.Insecure {
-		opts = append(opts, otlploghttp.WithInsecure())
-	}
-	if len(config.HttpHeader) > 0 {
-		opts = append(opts, otlploghttp.WithHeaders(config.HttpHeader))
-	}
-
-	// Create OTLP HTTP exporter for sending logs to OpenTelemetry collector
-	exporter, err := otlploghttp.New(ctx, opts...)
+	// Create OTLP exporter for sending logs to OpenTelemetry collector
+	exporter, err := newLogExporter(ctx, config)
 	if err != nil {
-		stdLog.Fatalf("[error] Failed to create exporter for Logger: %v", err.Error())
+		return nil, nil, fmt.Errorf("failed to create exporter for Logger: %v", err)
 	}
 
 	// Create resource with service metadata
-	resource := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName(config.ServiceName),
-		semconv.ServiceVersion(config.ServiceVersion),
-		attribute.String("host.ip", getLocalIP()),
-	)
+	resource := newResource(config.ServiceName, config.ServiceVersion, config.ResourceAttributes)
 
 	// Create Logger provider with batch processor for efficient log export
+	batchProcessorOpts := make([]log.BatchProcessorOption, 0)
+	if config.MaxQueueSize > 0 {
+		batchProcessorOpts = append(batchProcessorOpts, log.WithMaxQueueSize(config.MaxQueueSize))
+	}
+	if config.BatchTimeout > 0 {
+		batchProcessorOpts = append(batchProcessorOpts, log.WithExportInterval(config.BatchTimeout))
+	}
+	if config.MaxExportBatchSize > 0 {
+		batchProcessorOpts = append(batchProcessorOpts, log.WithExportMaxBatchSize(config.MaxExportBatchSize))
+	}
 	loggerProvider := log.NewLoggerProvider(
-		log.WithProcessor(log.NewBatchProcessor(exporter)),
+		log.WithProcessor(log.NewBatchProcessor(&exportHealthLogExporter{exporter, health.track(exportSignalLogs)}, batchProcessorOpts...)),
 		log.WithResource(resource),
 	)
 
@@ -99,83 +228,144 @@ func initLogger(config *LoggerConfig) (*slog.Logger, func(ctx context.Context))
 		otelHandler,
 	}
 
-	writers := []io.Writer{os.Stdout}
+	writers := []io.Writer{}
+	if !config.DisableStdout {
+		writers = append(writers, os.Stdout)
+	}
 
-	// Configure log level for local handler
-	localHandlerOption := slog.HandlerOptions{}
-	switch config.LocalLogLevel {
-	case LOG_LEVEL_INFO:
-		{
-			localHandlerOption.Level = slog.LevelInfo
-		}
-	case LOG_LEVEL_WARN:
-		{
-			localHandlerOption.Level = slog.LevelWarn
-		}
-	case LOG_LEVEL_DEBUG:
-		{
-			localHandlerOption.Level = slog.LevelDebug
-		}
-	case LOG_LEVEL_ERROR:
-		{
-			localHandlerOption.Level = slog.LevelError
-		}
-	default:
-		{
-			localHandlerOption.Level = slog.LevelInfo
-		}
+	// Configure log level for local handler, level can be changed at runtime by SetLogLevel
+	logLevelVar.Set(toSlogLevel(config.LocalLogLevel))
+	localHandlerOption := slog.HandlerOptions{
+		Level: &logLevelVar,
 	}
 
-	var logFile *os.File
+	var logFile io.WriteCloser
 	// Setup local file logging
 	if config.LocalLogFile != "" {
 		// Create log directory if it doesn't exist
 		if err := os.MkdirAll(filepath.Dir(config.LocalLogFile), 0755); err != nil {
-			stdLog.Fatalf("[error] Failed to create local log file dir for Logger: %v", err.Error())
+			loggerProvider.Shutdown(ctx)
+			return nil, nil, fmt.Errorf("failed to create local log file dir for Logger: %v", err)
 		}
 
-		// Open log file for writing
-		file, err := os.OpenFile(config.LocalLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
-		if err != nil {
-			stdLog.Fatalf("[error] Failed to open local log file for Logger: %v", err.Error())
+		if config.isRotationEnabled() {
+			// Use rotating writer, log file is rotated by size and old files are cleaned up by count and age
+			logFile = &lumberjack.Logger{
+				Filename:   config.LocalLogFile,
+				MaxSize:    config.MaxSizeMB,
+				MaxBackups: config.MaxBackups,
+				MaxAge:     config.MaxAgeDays,
+			}
+		} else {
+			// Open log file for writing
+			file, err := os.OpenFile(config.LocalLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
+			if err != nil {
+				loggerProvider.Shutdown(ctx)
+				return nil, nil, fmt.Errorf("failed to open local log file for Logger: %v", err)
+			}
+			logFile = file
 		}
-		logFile = file
 		writers = append(writers, logFile)
 	}
 
-	// Write to both stdout and file
-	multiWriter := io.MultiWriter(writers...)
+	// Write to both stdout and file, local handler is omitted if both are disabled
+	if len(writers) > 0 {
+		multiWriter := io.MultiWriter(writers...)
 
-	// Create JSON handler for local logging
-	localHandler := slog.NewJSONHandler(multiWriter, &localHandlerOption)
-	multiHandler = append(multiHandler, localHandler)
+		// Create JSON (or text) handler for local logging
+		var localHandler slog.Handler
+		if config.LocalLogFormat == LOG_FORMAT_TEXT {
+			localHandler = slog.NewTextHandler(multiWriter, &localHandlerOption)
+		} else {
+			localHandler = slog.NewJSONHandler(multiWriter, &localHandlerOption)
+		}
+		multiHandler = append(multiHandler, localHandler)
+	}
 
 	// Init Logger with multi handler, cleanup function for Logger
-	logger := slog.New(newMultiHandler(multiHandler...))
+	redactKeys := config.RedactKeys
+	if len(redactKeys) == 0 {
+		redactKeys = defaultLogRedactKeys
+	}
+	logger := slog.New(newMultiHandler(redactKeys, multiHandler...))
 	shutdown := func(ctx context.Context) {
 		if err := loggerProvider.Shutdown(ctx); err != nil {
 			stdLog.Printf("[error] Failed to shut down Logger provider: %v", err)
 		}
 		if logFile != nil {
-			logFile.Close()
+			if err := logFile.Close(); err != nil {
+				stdLog.Printf("[error] Failed to close local log file: %v", err)
+			}
 		}
 	}
 
 	// Return Logger and cleanup function for Logger
-	return logger, shutdown
+	return logger, shutdown, nil
+}
+
+// newLogExporter creates OTLP exporter for Logger by the configured protocol.
+func newLogExporter(ctx context.Context, config *LoggerConfig) (log.Exporter, error) {
+	switch config.Protocol {
+	case "", EXPORT_PROTOCOL_HTTP:
+		{
+			opts := []otlploghttp.Option{
+				otlploghttp.WithEndpoint(config.EndPoint),
+			}
+			if config.Insecure {
+				opts = append(opts, otlploghttp.WithInsecure())
+			}
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlploghttp.WithHeaders(config.HttpHeader))
+			}
+			return otlploghttp.New(ctx, opts...)
+		}
+	case EXPORT_PROTOCOL_GRPC:
+		{
+			opts := []otlploggrpc.Option{
+				otlploggrpc.WithEndpoint(config.EndPoint),
+			}
+			if config.Insecure {
+				opts = append(opts, otlploggrpc.WithInsecure())
+			}
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlploggrpc.WithHeaders(config.HttpHeader))
+			}
+			return otlploggrpc.New(ctx, opts...)
+		}
+	default:
+		{
+			return nil, fmt.Errorf("export protocol '%s' is not valid", config.Protocol)
+		}
+	}
 }
 
 // multiHandler dispatches log records to multiple handlers.
 type multiHandler struct {
-	handlers []slog.Handler
+	handlers   []slog.Handler
+	redactKeys map[string]struct{} // Lower case attribute keys whose values are redacted
+	groups     []handlerGroup      // Groups opened by WithGroup, applied in Handle so enriched attributes stay at top level
 }
 
-func newMultiHandler(handlers ...slog.Handler) *multiHandler {
-	return &multiHandler{handlers: handlers}
+// handlerGroup is a group opened by WithGroup with attributes added to it by WithAttrs.
+type handlerGroup struct {
+	name  string
+	attrs []slog.Attr
 }
 
-// Enabled returns true if any handler is enabled for the given level.
+func newMultiHandler(redactKeys []string, handlers ...slog.Handler) *multiHandler {
+	redactKeySet := make(map[string]struct{}, len(redactKeys))
+	for _, key := range redactKeys {
+		redactKeySet[strings.ToLower(key)] = struct{}{}
+	}
+	return &multiHandler{handlers: handlers, redactKeys: redactKeySet}
+}
+
+// Enabled returns true if the given level is not below current log level and any handler is enabled for it.
 func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < logLevelVar.Level() {
+		return false
+	}
+
 	for _, handler := range h.handlers {
 		if handler.Enabled(ctx, level) {
 			return true
@@ -184,16 +374,34 @@ func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
 	return false
 }
 
-// Handle enriches the log record with trace context and dispatches to all handlers.
+// Handle redacts sensitive attributes, enriches the log record with trace context and dispatches to all handlers.
 func (h *multiHandler) Handle(ctx context.Context, record slog.Record) error {
 	traceID, spanID := getTraceInfo(ctx)
 
-	// Clone and enrich the record with additional attributes
-	r := record.Clone()
+	// Copy the record with redacted attributes and enrich it with additional attributes
+	r := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	attrs := make([]slog.Attr, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, h.redactAttr(attr))
+		return true
+	})
+	// Nest record attributes into opened groups (innermost first), trace_id, span_id, client_ip, identity and baggage are not grouped
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		groupAttrs := append(slices.Clone(h.groups[i].attrs), attrs...)
+		attrs = []slog.Attr{{Key: h.groups[i].name, Value: slog.GroupValue(groupAttrs...)}}
+	}
+	r.AddAttrs(attrs...)
 	r.AddAttrs(
 		slog.String("trace_id", traceID),
 		slog.String("span_id", spanID),
 	)
+	if clientIP := getClientIPFromCtx(ctx); clientIP != "" {
+		r.AddAttrs(slog.String("client_ip", clientIP))
+	}
+	r.AddAttrs(getIdentityLogAttrs(ctx)...)
+	if baggageAttrs := getBaggageAttrs(ctx); len(baggageAttrs) > 0 {
+		r.AddAttrs(h.redactAttr(slog.Group("baggage", baggageAttrs...)))
+	}
 
 	// Dispatch to all handlers
 	for _, handler := range h.handlers {
@@ -205,19 +413,58 @@ func (h *multiHandler) Handle(ctx context.Context, record slog.Record) error {
 }
 
 func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redactedAttrs := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redactedAttrs[i] = h.redactAttr(attr)
+	}
+
+	// Attributes in a group are kept until Handle, where groups are applied
+	if len(h.groups) > 0 {
+		groups := slices.Clone(h.groups)
+		last := &groups[len(groups)-1]
+		last.attrs = append(slices.Clone(last.attrs), redactedAttrs...)
+		return &multiHandler{handlers: h.handlers, redactKeys: h.redactKeys, groups: groups}
+	}
+
 	handlers := make([]slog.Handler, len(h.handlers))
 	for i, handler := range h.handlers {
-		handlers[i] = handler.WithAttrs(attrs)
+		handlers[i] = handler.WithAttrs(redactedAttrs)
 	}
-	return &multiHandler{handlers: handlers}
+	return &multiHandler{handlers: handlers, redactKeys: h.redactKeys}
 }
 
+// WithGroup opens a group for attributes of following records, it is not passed to handlers,
+// so attributes enriched by Handle (trace_id, span_id, client_ip, identity, baggage) are not grouped.
 func (h *multiHandler) WithGroup(name string) slog.Handler {
-	handlers := make([]slog.Handler, len(h.handlers))
-	for i, handler := range h.handlers {
-		handlers[i] = handler.WithGroup(name)
+	if name == "" {
+		return h
+	}
+
+	groups := append(slices.Clone(h.groups), handlerGroup{name: name})
+	return &multiHandler{handlers: h.handlers, redactKeys: h.redactKeys, groups: groups}
+}
+
+// redactAttr replaces value of sensitive attribute, attributes in groups are redacted recursively.
+func (h *multiHandler) redactAttr(attr slog.Attr) slog.Attr {
+	if len(h.redactKeys) == 0 {
+		return attr
+	}
+
+	if _, ok := h.redactKeys[strings.ToLower(attr.Key)]; ok {
+		return slog.String(attr.Key, logRedactedValue)
+	}
+
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Value.Kind() == slog.KindGroup {
+		groupAttrs := attr.Value.Group()
+		redactedAttrs := make([]slog.Attr, len(groupAttrs))
+		for i, groupAttr := range groupAttrs {
+			redactedAttrs[i] = h.redactAttr(groupAttr)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redactedAttrs...)}
 	}
-	return &multiHandler{handlers: handlers}
+	return attr
 }
 
 // Context-aware logging functions.
@@ -282,21 +529,104 @@ func (o *Observer) ErrorLog(format string, args ...any) {
 	o.logWithMeta(context.Background(), slog.LevelError, format, args...)
 }
 
+// Structured logging functions.
+// These functions attach attributes as separate fields instead of formatting them into message.
+
+// InfoLogKV logs an informational message with trace context and structured attributes.
+//
+// Example:
+//
+//	observer.InfoLogKV(ctx, "User logged in", map[string]any{"username": "john.doe"})
+func (o *Observer) InfoLogKV(ctx context.Context, msg string, attrs map[string]any) {
+	o.logKVWithMeta(ctx, slog.LevelInfo, msg, attrs)
+}
+
+// WarnLogKV logs a warning message with trace context and structured attributes.
+//
+// Example:
+//
+//	observer.WarnLogKV(ctx, "User logged in", map[string]any{"username": "john.doe"})
+func (o *Observer) WarnLogKV(ctx context.Context, msg string, attrs map[string]any) {
+	o.logKVWithMeta(ctx, slog.LevelWarn, msg, attrs)
+}
+
+// DebugLogKV logs a debug message with trace context and structured attributes.
+//
+// Example:
+//
+//	observer.DebugLogKV(ctx, "User logged in", map[string]any{"username": "john.doe"})
+func (o *Observer) DebugLogKV(ctx context.Context, msg string, attrs map[string]any) {
+	o.logKVWithMeta(ctx, slog.LevelDebug, msg, attrs)
+}
+
+// ErrorLogKV logs an error message with trace context and structured attributes.
+//
+// Example:
+//
+//	observer.ErrorLogKV(ctx, "User logged in", map[string]any{"username": "john.doe"})
+func (o *Observer) ErrorLogKV(ctx context.Context, msg string, attrs map[string]any) {
+	o.logKVWithMeta(ctx, slog.LevelError, msg, attrs)
+}
+
+// WithLogGroup returns an Observer whose logs nest their attributes (including "meta") under the given group.
+// trace_id, span_id, client_ip, identity and baggage stay at top level, so logs can still be correlated.
+// The returned Observer shares all components with o, only o should be shut down.
+//
+// Example:
+//
+//	dbObserver := observer.WithLogGroup("db")
+//	dbObserver.InfoLogKV(ctx, "Query executed", map[string]any{"table": "users"})
+func (o *Observer) WithLogGroup(name string) IObserver {
+	logger := o.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	grouped := *o
+	grouped.logger = logger.WithGroup(name)
+	return &grouped
+}
+
+// callerMeta returns "file:line" of the log call site, skipping additional frames configured by CallerSkip.
+func (o *Observer) callerMeta() string {
+	// Skip callerMeta, logWithMeta (or logKVWithMeta) and the exported log function
+	_, path, numLine, _ := runtime.Caller(3 + o.logCallerSkip)
+	if !o.logFullPath {
+		path = filepath.Base(path)
+	}
+	return fmt.Sprintf("%s:%d", path, numLine)
+}
+
 // logWithMeta adds source file location to log entries.
 func (o *Observer) logWithMeta(ctx context.Context, level slog.Level, format string, args ...any) {
-	if o.logger == nil {
-		stdLog.Printf("[error] Failed to use Logger: %v", ErrLoggerUnconfigured)
-		return
+	logger := o.logger
+	if logger == nil {
+		logger = defaultLogger
 	}
 
-	_, path, numLine, _ := runtime.Caller(2)
-	srcFile := filepath.Base(path)
-	meta := fmt.Sprintf("%s:%d", srcFile, numLine)
+	meta := o.callerMeta()
 	msg := fmt.Sprintf(format, args...)
-	o.logger.LogAttrs(
+	logger.LogAttrs(
 		ctx,
 		level,
 		msg,
 		slog.String("meta", meta),
 	)
 }
+
+// logKVWithMeta adds source file location and structured attributes to log entries.
+func (o *Observer) logKVWithMeta(ctx context.Context, level slog.Level, msg string, attrMap map[string]any) {
+	logger := o.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+
+	meta := o.callerMeta()
+	attrs := append([]slog.Attr{slog.String("meta", meta)}, mapToLogAttr(attrMap)...)
+	logger.LogAttrs(
+		ctx,
+		level,
+		msg,
+		attrs...,
+	)
+}
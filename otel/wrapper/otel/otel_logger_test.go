@@ -0,0 +1,38 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestWithLogGroupKeepsClientIPAtTopLevel(t *testing.T) {
+	var logBuf bytes.Buffer
+	observer := &Observer{logger: slog.New(newMultiHandler(nil, slog.NewJSONHandler(&logBuf, nil)))}
+
+	ctx := ContextWithClientIP(context.Background(), "203.0.113.7")
+	observer.WithLogGroup("db").InfoLogKV(ctx, "Query executed", map[string]any{"table": "users"})
+
+	var entry map[string]any
+	if err := json.Unmarshal(logBuf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode log entry %q: %v", logBuf.String(), err)
+	}
+	if entry["client_ip"] != "203.0.113.7" {
+		t.Errorf("client_ip = %v, expected 203.0.113.7 at top level: %s", entry["client_ip"], logBuf.String())
+	}
+	if _, ok := entry["trace_id"]; !ok {
+		t.Errorf("trace_id is not at top level: %s", logBuf.String())
+	}
+	group, ok := entry["db"].(map[string]any)
+	if !ok {
+		t.Fatalf("group 'db' not found: %s", logBuf.String())
+	}
+	if group["table"] != "users" {
+		t.Errorf("db.table = %v, expected users", group["table"])
+	}
+	if _, ok := group["client_ip"]; ok {
+		t.Errorf("client_ip is nested in group 'db': %s", logBuf.String())
+	}
+}
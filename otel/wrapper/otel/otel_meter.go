@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -11,11 +12,11 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
-	"go.opentelemetry.io/otel/sdk/resource"
-	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
 )
 
 // Error definitions for Meter.
@@ -63,58 +64,94 @@ const (
 	METRIC_TYPE_GAUGE MetricType = "gauge"
 )
 
+// MetricValueKind defines the value type of a gauge metric.
+type MetricValueKind string
+
+// Metric value kind definitions for gauge metric.
+const (
+	// METRIC_VALUE_KIND_FLOAT64 is used for creating a gauge with float64 values (default).
+	METRIC_VALUE_KIND_FLOAT64 MetricValueKind = "float64"
+	// METRIC_VALUE_KIND_INT64 is used for creating a gauge with int64 values.
+	METRIC_VALUE_KIND_INT64 MetricValueKind = "int64"
+)
+
 // MeterConfig configures the metrics collection component
 type MeterConfig struct {
 	ServiceName    string            // Name of the service
 	ServiceVersion string            // Version of the service
 	EndPoint       string            // OTLP endpoint for exporting telemetry data
 	Insecure       bool              // Allow HTTP schema, instead of HTTPS
-	HttpHeader     map[string]string // Additional HTTP headers
+	HttpHeader     map[string]string // Additional HTTP headers (gRPC metadata when using gRPC protocol)
+	Protocol       ExportProtocol    // OTLP protocol for exporting (default: EXPORT_PROTOCOL_HTTP)
+
+	ResourceAttributes map[string]string // Custom resource attributes added to all exported data, e.g. "deployment.environment", "k8s.pod.name"
 
 	MetricCollectionInterval time.Duration // Interval for collecting and exporting metrics
 	MetricDefs               []*MetricDef  // List of metric definitions to register
 }
 
-// initMeter initializes the Meter and metricCollectorManager, returns Meter, metricCollectorManager and a cleanup function.
-// Metrics are collected periodically and exported via OTLP HTTP.
-func initMeter(config *MeterConfig) (metric.Meter, *metricCollectorManager, func(ctx context.Context)) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	opts := []otlpmetrichttp.Option{
-		otlpmetrichttp.WithEndpoint(config.EndPoint),
+// Validate checks required fields and values of MeterConfig and its metric definitions.
+func (config *MeterConfig) Validate() error {
+	if config.ServiceName == "" {
+		return errors.New("service name is required")
 	}
-	if config.Insecure {
-		opts = append(opts, otlpmetrichttp.WithInsecure())
+	if config.EndPoint == "" {
+		return errors.New("end point is required")
 	}
-	if len(config.HttpHeader) > 0 {
-		opts = append(opts, otlpmetrichttp.WithHeaders(config.HttpHeader))
+	if err := config.Protocol.validate(); err != nil {
+		return err
 	}
+	if err := validateResourceAttrs(config.ResourceAttributes); err != nil {
+		return err
+	}
+
+	names := make(map[MetricName]struct{}, len(config.MetricDefs))
+	for i, metricDef := range config.MetricDefs {
+		if metricDef == nil {
+			return fmt.Errorf("metric definition at index %d is nil", i)
+		}
+		if err := metricDef.validate(); err != nil {
+			return err
+		}
+		if _, ok := names[metricDef.Name]; ok {
+			return fmt.Errorf("metric '%s' is duplicated", metricDef.Name)
+		}
+		names[metricDef.Name] = struct{}{}
+	}
+	return nil
+}
+
+// initMeter initializes the Meter and metricCollectorManager, returns Meter, metricCollectorManager and a cleanup function.
+// Metrics are collected periodically and exported via OTLP HTTP (or gRPC).
+func initMeter(config *MeterConfig, health *exportHealth, extraReaders ...sdkmetric.Reader) (metric.Meter, *metricCollectorManager, func(ctx context.Context), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Create OTLP HTTP exporter for sending metrics
-	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	// Create OTLP exporter for sending metrics
+	exporter, err := newMetricExporter(ctx, config)
 	if err != nil {
-		stdLog.Fatalf("[error] Failed to create exporter for Meter: %v", err)
+		return nil, nil, nil, fmt.Errorf("failed to create exporter for Meter: %v", err)
 	}
 
 	// Create resource with service metadata
-	resource := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName(config.ServiceName),
-		semconv.ServiceVersion(config.ServiceVersion),
-		attribute.String("host.ip", getLocalIP()),
-	)
+	resource := newResource(config.ServiceName, config.ServiceVersion, config.ResourceAttributes)
 
 	// Create Meter provider with periodic reader for automatic metric collection
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(config.MetricCollectionInterval))),
+	meterProviderOpts := []sdkmetric.Option{
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(&exportHealthMetricExporter{exporter, health.track(exportSignalMetrics)}, sdkmetric.WithInterval(config.MetricCollectionInterval))),
 		sdkmetric.WithResource(resource),
-	)
-
-	otel.SetMeterProvider(meterProvider)
+		sdkmetric.WithView(histogramBucketViews(config.MetricDefs)...),
+		// Values recorded under a sampled span carry exemplars with trace/span IDs (linking metrics to traces)
+		sdkmetric.WithExemplarFilter(exemplar.TraceBasedFilter),
+	}
+	// Additional readers (e.g. Prometheus) collect the same metrics alongside OTLP push
+	for _, reader := range extraReaders {
+		meterProviderOpts = append(meterProviderOpts, sdkmetric.WithReader(reader))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterProviderOpts...)
 
 	// Init Meter, Metric collector manager and cleanup function for Meter
-	meter := otel.Meter(config.ServiceName)
+	meter := meterProvider.Meter(config.ServiceName)
 	metricCollectorManager := newMetricCollectorManager()
 	shutdown := func(ctx context.Context) {
 		if err := meterProvider.Shutdown(ctx); err != nil {
@@ -124,40 +161,64 @@ func initMeter(config *MeterConfig) (metric.Meter, *metricCollectorManager, func
 
 	// Register all configured metrics
 	for _, metricDef := range config.MetricDefs {
-		switch metricDef.Type {
-		case METRIC_TYPE_COUNTER:
-			{
-				if err := metricCollectorManager.registerCounter(meter, metricDef); err != nil {
-					stdLog.Fatalf("[error] Failed to register Counter '%s' for Meter: %v", metricDef.Name, err)
-				}
+		if err := metricCollectorManager.register(meter, metricDef); err != nil {
+			shutdown(ctx)
+			return nil, nil, nil, fmt.Errorf("failed to register metric '%s' for Meter: %v", metricDef.Name, err)
+		}
+	}
+
+	// Register health metrics of telemetry pipeline (custom_otel_export_errors_total, custom_otel_export_healthy)
+	if err := health.registerMetrics(meter); err != nil {
+		shutdown(ctx)
+		return nil, nil, nil, fmt.Errorf("failed to register export health metrics for Meter: %v", err)
+	}
+
+	// Register counter of attribute sets dropped by MaxAttrCardinality (custom_otel_metric_attr_sets_dropped_total)
+	if err := metricCollectorManager.registerDroppedAttrSetsMetric(meter); err != nil {
+		shutdown(ctx)
+		return nil, nil, nil, fmt.Errorf("failed to register dropped attribute sets metric for Meter: %v", err)
+	}
+
+	otel.SetMeterProvider(meterProvider)
+
+	// Return Meter, metricCollectorManager and cleanup function for Meter
+	return meter, metricCollectorManager, shutdown, nil
+}
+
+// newMetricExporter creates OTLP exporter for Meter by the configured protocol.
+func newMetricExporter(ctx context.Context, config *MeterConfig) (sdkmetric.Exporter, error) {
+	switch config.Protocol {
+	case "", EXPORT_PROTOCOL_HTTP:
+		{
+			opts := []otlpmetrichttp.Option{
+				otlpmetrichttp.WithEndpoint(config.EndPoint),
 			}
-		case METRIC_TYPE_UP_DOWN_COUNTER:
-			{
-				if err := metricCollectorManager.registerUpDownCounter(meter, metricDef); err != nil {
-					stdLog.Fatalf("[error] Failed to register UpDownCounter '%s' for Meter: %v", metricDef.Name, err)
-				}
+			if config.Insecure {
+				opts = append(opts, otlpmetrichttp.WithInsecure())
 			}
-		case METRIC_TYPE_HISTOGRAM:
-			{
-				if err := metricCollectorManager.registerHistogram(meter, metricDef); err != nil {
-					stdLog.Fatalf("[error] Failed to register Histogram '%s' for Meter: %v", metricDef.Name, err)
-				}
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlpmetrichttp.WithHeaders(config.HttpHeader))
 			}
-		case METRIC_TYPE_GAUGE:
-			{
-				if err := metricCollectorManager.registerGauge(meter, metricDef); err != nil {
-					stdLog.Fatalf("[error] Failed to register Gauge '%s' for Meter: %v", metricDef.Name, err)
-				}
+			return otlpmetrichttp.New(ctx, opts...)
+		}
+	case EXPORT_PROTOCOL_GRPC:
+		{
+			opts := []otlpmetricgrpc.Option{
+				otlpmetricgrpc.WithEndpoint(config.EndPoint),
+			}
+			if config.Insecure {
+				opts = append(opts, otlpmetricgrpc.WithInsecure())
 			}
-		default:
-			{
-				stdLog.Fatalf("[error] Failed to register metric: Metric type '%s' is not valid", metricDef.Type)
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlpmetricgrpc.WithHeaders(config.HttpHeader))
 			}
+			return otlpmetricgrpc.New(ctx, opts...)
+		}
+	default:
+		{
+			return nil, fmt.Errorf("export protocol '%s' is not valid", config.Protocol)
 		}
 	}
-
-	// Return Meter, metricCollectorManager and cleanup function for Meter
-	return meter, metricCollectorManager, shutdown
 }
 
 // metricCollectorManager manages all registered metrics.
@@ -166,6 +227,37 @@ type metricCollectorManager struct {
 	upDownCounters map[MetricName]metric.Int64UpDownCounter
 	histograms     map[MetricName]metric.Float64Histogram
 	gauges         map[MetricName]*observableGaugeState
+	intGauges      map[MetricName]*observableIntGaugeState
+
+	allowedAttrs      map[MetricName]map[string]struct{}   // Allowed attribute keys per metric, metric without entry allows all
+	attrCardinalities map[MetricName]*attrCardinalityState // Attribute cardinality limit per metric, metric without entry is unbounded
+	identityAttrs     map[MetricName]map[string]struct{}   // Identity attribute keys per metric, metric without entry records no identity
+	histogramStats    map[MetricName]*histogramStatState   // Local count and sum per histogram, histogram without entry is not tracked
+
+	droppedAttrWarnings sync.Map // Set of droppedAttrKey already warned about, so dropping on hot path logs once
+
+	mu sync.RWMutex // Guards metric maps against unregistering at runtime
+}
+
+// droppedAttrKey identifies an attribute key dropped from a metric by AllowedAttrs.
+type droppedAttrKey struct {
+	name MetricName
+	key  attribute.Key
+}
+
+// attrCardinalityState tracks distinct attribute sets recorded for a metric to limit its cardinality.
+type attrCardinalityState struct {
+	maxAttrCardinality int
+	attrSets           map[string]struct{}
+	dropped            int64 // Number of records dropped since limit was reached
+	mu                 sync.Mutex
+}
+
+// histogramStatState keeps running count and sum of values recorded to a histogram, independent of SDK aggregation.
+type histogramStatState struct {
+	count int64
+	sum   float64
+	mu    sync.Mutex
 }
 
 // gaugeValue stores the current gauge value with metadata.
@@ -177,9 +269,25 @@ type gaugeValue struct {
 
 // observableGaugeState wraps an observable gauge with its current value.
 type observableGaugeState struct {
-	instrument  metric.Float64ObservableGauge
-	currentVals map[string]*gaugeValue
-	mu          sync.RWMutex
+	instrument   metric.Float64ObservableGauge
+	registration metric.Registration // Callback registration, used for unregistering gauge
+	currentVals  map[string]*gaugeValue
+	mu           sync.RWMutex
+}
+
+// intGaugeValue stores the current int gauge value with metadata.
+type intGaugeValue struct {
+	value     int64
+	attrs     []attribute.KeyValue
+	updatedAt time.Time
+}
+
+// observableIntGaugeState wraps an observable int gauge with its current value.
+type observableIntGaugeState struct {
+	instrument   metric.Int64ObservableGauge
+	registration metric.Registration // Callback registration, used for unregistering gauge
+	currentVals  map[string]*intGaugeValue
+	mu           sync.RWMutex
 }
 
 func newMetricCollectorManager() *metricCollectorManager {
@@ -188,6 +296,12 @@ func newMetricCollectorManager() *metricCollectorManager {
 		upDownCounters: make(map[MetricName]metric.Int64UpDownCounter),
 		histograms:     make(map[MetricName]metric.Float64Histogram),
 		gauges:         make(map[MetricName]*observableGaugeState),
+		intGauges:      make(map[MetricName]*observableIntGaugeState),
+
+		allowedAttrs:      make(map[MetricName]map[string]struct{}),
+		attrCardinalities: make(map[MetricName]*attrCardinalityState),
+		identityAttrs:     make(map[MetricName]map[string]struct{}),
+		histogramStats:    make(map[MetricName]*histogramStatState),
 	}
 }
 
@@ -197,6 +311,100 @@ type MetricDef struct {
 	Name        MetricName // Name of metric
 	Description string     // Description of metric
 	Unit        string     // Unit of metric
+
+	ValueKind MetricValueKind // Value type of gauge metric (empty: METRIC_VALUE_KIND_FLOAT64)
+
+	AllowedAttrs []string  // Allowed attribute keys of metric, other keys are dropped (empty: allow all)
+	Buckets      []float64 // Explicit bucket boundaries of histogram metric (empty: SDK default buckets)
+
+	MaxAttrCardinality int // Max distinct attribute sets of metric, recording a new attribute set beyond it is dropped (0: unbounded)
+
+	// Identity attributes (IDENTITY_ATTR_*) added from ctx set by WithIdentity, bypassing AllowedAttrs (empty: none).
+	// user_id is usually unbounded, prefer tenant_id only or set MaxAttrCardinality.
+	IdentityAttrs []string
+
+	LocalStats bool // Keep running count and sum of histogram metric locally for HistogramSnapshot (false: not tracked)
+}
+
+// validate checks required fields and values of MetricDef.
+func (metricDef *MetricDef) validate() error {
+	if metricDef.Name == "" {
+		return errors.New("metric name is required")
+	}
+
+	switch metricDef.Type {
+	case METRIC_TYPE_COUNTER, METRIC_TYPE_UP_DOWN_COUNTER, METRIC_TYPE_HISTOGRAM, METRIC_TYPE_GAUGE:
+	default:
+		return fmt.Errorf("metric type '%s' of metric '%s' is not valid", metricDef.Type, metricDef.Name)
+	}
+
+	switch metricDef.ValueKind {
+	case "", METRIC_VALUE_KIND_FLOAT64, METRIC_VALUE_KIND_INT64:
+	default:
+		return fmt.Errorf("value kind '%s' of metric '%s' is not valid", metricDef.ValueKind, metricDef.Name)
+	}
+
+	if !sort.Float64sAreSorted(metricDef.Buckets) {
+		return fmt.Errorf("buckets of metric '%s' must be sorted in increasing order", metricDef.Name)
+	}
+
+	if metricDef.MaxAttrCardinality < 0 {
+		return fmt.Errorf("max attribute cardinality of metric '%s' must be non-negative", metricDef.Name)
+	}
+
+	for _, key := range metricDef.IdentityAttrs {
+		if key != IDENTITY_ATTR_TENANT_ID && key != IDENTITY_ATTR_USER_ID {
+			return fmt.Errorf("identity attribute '%s' of metric '%s' is not valid", key, metricDef.Name)
+		}
+	}
+	return nil
+}
+
+// histogramBucketViews creates a View for each histogram definition with custom bucket boundaries.
+// Views must be set when creating Meter provider, so they are built from MetricDefs before registering metrics.
+func histogramBucketViews(metricDefs []*MetricDef) []sdkmetric.View {
+	views := []sdkmetric.View{}
+	for _, metricDef := range metricDefs {
+		if metricDef.Type != METRIC_TYPE_HISTOGRAM || len(metricDef.Buckets) == 0 {
+			continue
+		}
+
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: metricDef.Name.Get().String()},
+			sdkmetric.Stream{
+				Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+					Boundaries: metricDef.Buckets,
+				},
+			},
+		))
+	}
+	return views
+}
+
+// register creates and registers a metric for the given meter by its type.
+func (mcm *metricCollectorManager) register(meter metric.Meter, metricDef *MetricDef) error {
+	switch metricDef.Type {
+	case METRIC_TYPE_COUNTER:
+		{
+			return mcm.registerCounter(meter, metricDef)
+		}
+	case METRIC_TYPE_UP_DOWN_COUNTER:
+		{
+			return mcm.registerUpDownCounter(meter, metricDef)
+		}
+	case METRIC_TYPE_HISTOGRAM:
+		{
+			return mcm.registerHistogram(meter, metricDef)
+		}
+	case METRIC_TYPE_GAUGE:
+		{
+			return mcm.registerGauge(meter, metricDef)
+		}
+	default:
+		{
+			return fmt.Errorf("metric type '%s' is not valid", metricDef.Type)
+		}
+	}
 }
 
 // registerCounter creates and registers a counter metric for the given meter.
@@ -218,6 +426,9 @@ func (mcm *metricCollectorManager) registerCounter(meter metric.Meter, metricDef
 	}
 
 	mcm.counters[metricDef.Name.Get()] = counter
+	mcm.setAllowedAttrs(metricDef)
+	mcm.setAttrCardinalityLimit(metricDef)
+	mcm.setIdentityAttrs(metricDef)
 	return nil
 }
 
@@ -240,6 +451,9 @@ func (mcm *metricCollectorManager) registerUpDownCounter(meter metric.Meter, met
 	}
 
 	mcm.upDownCounters[metricDef.Name.Get()] = updown
+	mcm.setAllowedAttrs(metricDef)
+	mcm.setAttrCardinalityLimit(metricDef)
+	mcm.setIdentityAttrs(metricDef)
 	return nil
 }
 
@@ -262,14 +476,37 @@ func (mcm *metricCollectorManager) registerHistogram(meter metric.Meter, metricD
 	}
 
 	mcm.histograms[metricDef.Name.Get()] = histo
+	if metricDef.LocalStats {
+		mcm.histogramStats[metricDef.Name.Get()] = &histogramStatState{}
+	}
+	mcm.setAllowedAttrs(metricDef)
+	mcm.setAttrCardinalityLimit(metricDef)
+	mcm.setIdentityAttrs(metricDef)
 	return nil
 }
 
 // registerGauge creates and registers a gauge metric with callback for the given meter.
+// The gauge value type is selected by ValueKind of metric definition.
 func (mcm *metricCollectorManager) registerGauge(meter metric.Meter, metricDef *MetricDef) error {
 	if _, exists := mcm.gauges[metricDef.Name.Get()]; exists {
 		return fmt.Errorf("gauge '%s' already exists", metricDef.Name)
 	}
+	if _, exists := mcm.intGauges[metricDef.Name.Get()]; exists {
+		return fmt.Errorf("gauge '%s' already exists", metricDef.Name)
+	}
+
+	switch metricDef.ValueKind {
+	case "", METRIC_VALUE_KIND_FLOAT64:
+		return mcm.registerFloatGauge(meter, metricDef)
+	case METRIC_VALUE_KIND_INT64:
+		return mcm.registerIntGauge(meter, metricDef)
+	default:
+		return fmt.Errorf("value kind '%s' of gauge '%s' is not valid", metricDef.ValueKind, metricDef.Name)
+	}
+}
+
+// registerFloatGauge creates and registers a float64 gauge metric with callback for the given meter.
+func (mcm *metricCollectorManager) registerFloatGauge(meter metric.Meter, metricDef *MetricDef) error {
 
 	opts := []metric.Float64ObservableGaugeOption{
 		metric.WithDescription(metricDef.Description),
@@ -289,10 +526,10 @@ func (mcm *metricCollectorManager) registerGauge(meter metric.Meter, metricDef *
 	}
 
 	// Register callback to observe gauge values during collection
-	_, err = meter.RegisterCallback(
+	gaugeState.registration, err = meter.RegisterCallback(
 		func(ctx context.Context, o metric.Observer) error {
-			gaugeState.mu.RLock()
-			defer gaugeState.mu.RUnlock()
+			gaugeState.mu.Lock()
+			defer gaugeState.mu.Unlock()
 
 			now := time.Now()
 
@@ -316,25 +553,332 @@ func (mcm *metricCollectorManager) registerGauge(meter metric.Meter, metricDef *
 	}
 
 	mcm.gauges[metricDef.Name.Get()] = gaugeState
+	mcm.setAllowedAttrs(metricDef)
+	mcm.setAttrCardinalityLimit(metricDef)
+	mcm.setIdentityAttrs(metricDef)
+	return nil
+}
+
+// registerIntGauge creates and registers an int64 gauge metric with callback for the given meter.
+func (mcm *metricCollectorManager) registerIntGauge(meter metric.Meter, metricDef *MetricDef) error {
+	opts := []metric.Int64ObservableGaugeOption{
+		metric.WithDescription(metricDef.Description),
+	}
+	if metricDef.Unit != "" {
+		opts = append(opts, metric.WithUnit(metricDef.Unit))
+	}
+
+	gauge, err := meter.Int64ObservableGauge(metricDef.Name.Get().String(), opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create gauge '%s': %v", metricDef.Name, err)
+	}
+
+	gaugeState := &observableIntGaugeState{
+		instrument:  gauge,
+		currentVals: make(map[string]*intGaugeValue),
+	}
+
+	// Register callback to observe gauge values during collection
+	gaugeState.registration, err = meter.RegisterCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			gaugeState.mu.Lock()
+			defer gaugeState.mu.Unlock()
+
+			now := time.Now()
+
+			for key, gaugeValue := range gaugeState.currentVals {
+				if now.Sub(gaugeValue.updatedAt) > defaultGaugeMetricTTL {
+					delete(gaugeState.currentVals, key)
+				}
+			}
+
+			for _, gaugeValue := range gaugeState.currentVals {
+				o.ObserveInt64(gaugeState.instrument, gaugeValue.value,
+					metric.WithAttributes(gaugeValue.attrs...),
+				)
+			}
+			return nil
+		},
+		gauge,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to register gauge callback '%s': %v", metricDef.Name, err)
+	}
+
+	mcm.intGauges[metricDef.Name.Get()] = gaugeState
+	mcm.setAllowedAttrs(metricDef)
+	mcm.setAttrCardinalityLimit(metricDef)
+	mcm.setIdentityAttrs(metricDef)
+	return nil
+}
+
+// gaugeSnapshots returns current (not expired) values of all gauges, ordered by name.
+func (mcm *metricCollectorManager) gaugeSnapshots() []GaugeSnapshot {
+	mcm.mu.RLock()
+	defer mcm.mu.RUnlock()
+
+	now := time.Now()
+	snapshots := make([]GaugeSnapshot, 0)
+	for name, gaugeState := range mcm.gauges {
+		gaugeState.mu.RLock()
+		for _, gaugeValue := range gaugeState.currentVals {
+			if now.Sub(gaugeValue.updatedAt) > defaultGaugeMetricTTL {
+				continue
+			}
+			snapshots = append(snapshots, newGaugeSnapshot(name, gaugeValue.value, gaugeValue.attrs, gaugeValue.updatedAt))
+		}
+		gaugeState.mu.RUnlock()
+	}
+	for name, gaugeState := range mcm.intGauges {
+		gaugeState.mu.RLock()
+		for _, gaugeValue := range gaugeState.currentVals {
+			if now.Sub(gaugeValue.updatedAt) > defaultGaugeMetricTTL {
+				continue
+			}
+			snapshots = append(snapshots, newGaugeSnapshot(name, float64(gaugeValue.value), gaugeValue.attrs, gaugeValue.updatedAt))
+		}
+		gaugeState.mu.RUnlock()
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Name != snapshots[j].Name {
+			return snapshots[i].Name < snapshots[j].Name
+		}
+		return snapshots[i].UpdatedAt.Before(snapshots[j].UpdatedAt)
+	})
+	return snapshots
+}
+
+// setAllowedAttrs stores the allowed attribute keys of the given metric definition.
+func (mcm *metricCollectorManager) setAllowedAttrs(metricDef *MetricDef) {
+	if len(metricDef.AllowedAttrs) == 0 {
+		return
+	}
+
+	allowedAttrs := make(map[string]struct{}, len(metricDef.AllowedAttrs))
+	for _, key := range metricDef.AllowedAttrs {
+		allowedAttrs[key] = struct{}{}
+	}
+	mcm.allowedAttrs[metricDef.Name.Get()] = allowedAttrs
+}
+
+// filterAttrs drops attributes which are not allowed for the given metric.
+func (mcm *metricCollectorManager) filterAttrs(name MetricName, attrs []attribute.KeyValue) []attribute.KeyValue {
+	mcm.mu.RLock()
+	allowedAttrs, ok := mcm.allowedAttrs[name.Get()]
+	mcm.mu.RUnlock()
+	if !ok {
+		return attrs
+	}
+
+	// Filter into a new slice, attrs may be shared by caller (e.g. Record*Attrs)
+	filteredAttrs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if _, ok := allowedAttrs[string(attr.Key)]; !ok {
+			if _, warned := mcm.droppedAttrWarnings.LoadOrStore(droppedAttrKey{name, attr.Key}, struct{}{}); !warned {
+				stdLog.Printf("[warning] Attribute key '%s' is not allowed for metric '%s', it will be dropped (logged once)", attr.Key, name)
+			}
+			continue
+		}
+		filteredAttrs = append(filteredAttrs, attr)
+	}
+	return filteredAttrs
+}
+
+// setIdentityAttrs stores the identity attribute keys of the given metric definition.
+func (mcm *metricCollectorManager) setIdentityAttrs(metricDef *MetricDef) {
+	if len(metricDef.IdentityAttrs) == 0 {
+		return
+	}
+
+	identityAttrs := make(map[string]struct{}, len(metricDef.IdentityAttrs))
+	for _, key := range metricDef.IdentityAttrs {
+		identityAttrs[key] = struct{}{}
+	}
+	mcm.identityAttrs[metricDef.Name.Get()] = identityAttrs
+}
+
+// addIdentityAttrs appends identity attributes in ctx which the given metric opts in, attrs is not modified.
+func (mcm *metricCollectorManager) addIdentityAttrs(ctx context.Context, name MetricName, attrs []attribute.KeyValue) []attribute.KeyValue {
+	mcm.mu.RLock()
+	identityAttrs, ok := mcm.identityAttrs[name.Get()]
+	mcm.mu.RUnlock()
+	if !ok {
+		return attrs
+	}
+
+	for _, attr := range getIdentityAttrs(ctx) {
+		if _, ok := identityAttrs[string(attr.Key)]; ok {
+			attrs = append(slices.Clip(attrs), attr)
+		}
+	}
+	return attrs
+}
+
+// setAttrCardinalityLimit stores the attribute cardinality limit of the given metric definition.
+func (mcm *metricCollectorManager) setAttrCardinalityLimit(metricDef *MetricDef) {
+	if metricDef.MaxAttrCardinality <= 0 {
+		return
+	}
+
+	mcm.attrCardinalities[metricDef.Name.Get()] = &attrCardinalityState{
+		maxAttrCardinality: metricDef.MaxAttrCardinality,
+		attrSets:           make(map[string]struct{}),
+	}
+}
+
+// checkAttrCardinality reports whether the attribute set can be recorded for the given metric.
+// A new attribute set is rejected when the metric already has max distinct attribute sets.
+func (mcm *metricCollectorManager) checkAttrCardinality(name MetricName, attrs []attribute.KeyValue) bool {
+	mcm.mu.RLock()
+	state, ok := mcm.attrCardinalities[name.Get()]
+	mcm.mu.RUnlock()
+	if !ok {
+		return true
+	}
+
+	key := hashAttrs(attrs)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if _, ok := state.attrSets[key]; ok {
+		return true
+	}
+	if len(state.attrSets) >= state.maxAttrCardinality {
+		// Log only when limit is first hit, further drops are counted in custom_otel_metric_attr_sets_dropped_total
+		if state.dropped == 0 {
+			stdLog.Printf("[warning] Attribute cardinality of metric '%s' reached %d, new attribute sets will be dropped (first: '%s')", name, state.maxAttrCardinality, key)
+		}
+		state.dropped++
+		return false
+	}
+
+	state.attrSets[key] = struct{}{}
+	return true
+}
+
+// registerDroppedAttrSetsMetric registers counter of records dropped by MaxAttrCardinality, with attribute metric per limited metric.
+func (mcm *metricCollectorManager) registerDroppedAttrSetsMetric(meter metric.Meter) error {
+	droppedCounter, err := meter.Int64ObservableCounter(
+		droppedAttrSetsMetricName.Get().String(),
+		metric.WithDescription("Number of metric records dropped by attribute cardinality limit"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create metric '%s': %v", droppedAttrSetsMetricName, err)
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		mcm.mu.RLock()
+		defer mcm.mu.RUnlock()
+
+		for name, state := range mcm.attrCardinalities {
+			state.mu.Lock()
+			dropped := state.dropped
+			state.mu.Unlock()
+			observer.ObserveInt64(droppedCounter, dropped, metric.WithAttributes(attribute.String("metric", name.String())))
+		}
+		return nil
+	}, droppedCounter)
+	if err != nil {
+		return fmt.Errorf("failed to register callback of metric '%s': %v", droppedAttrSetsMetricName, err)
+	}
+
 	return nil
 }
 
+// unregister removes the metric with the given name from all metric maps.
+// Gauge callback is unregistered, so the gauge is no longer observed on collection.
+func (mcm *metricCollectorManager) unregister(name MetricName) error {
+	mcm.mu.Lock()
+	defer mcm.mu.Unlock()
+
+	found := false
+	if _, ok := mcm.counters[name.Get()]; ok {
+		delete(mcm.counters, name.Get())
+		found = true
+	}
+	if _, ok := mcm.upDownCounters[name.Get()]; ok {
+		delete(mcm.upDownCounters, name.Get())
+		found = true
+	}
+	if _, ok := mcm.histograms[name.Get()]; ok {
+		delete(mcm.histograms, name.Get())
+		found = true
+	}
+	if gaugeState, ok := mcm.gauges[name.Get()]; ok {
+		if err := gaugeState.registration.Unregister(); err != nil {
+			return fmt.Errorf("failed to unregister gauge callback '%s': %v", name, err)
+		}
+		delete(mcm.gauges, name.Get())
+		found = true
+	}
+	if gaugeState, ok := mcm.intGauges[name.Get()]; ok {
+		if err := gaugeState.registration.Unregister(); err != nil {
+			return fmt.Errorf("failed to unregister gauge callback '%s': %v", name, err)
+		}
+		delete(mcm.intGauges, name.Get())
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("metric '%s' not found", name)
+	}
+	delete(mcm.allowedAttrs, name.Get())
+	delete(mcm.attrCardinalities, name.Get())
+	delete(mcm.identityAttrs, name.Get())
+	delete(mcm.histogramStats, name.Get())
+	return nil
+}
+
+// UnregisterMetric removes a registered metric at runtime.
+// Recording the metric after unregistering fails with a not found error log.
+// Note that synchronous instruments (counter, up-down counter, histogram) can not be removed from the SDK,
+// already recorded data points are still exported until the Meter provider is shut down.
+//
+// Example:
+//
+//	if err := observer.UnregisterMetric("migration_progress"); err != nil {
+//		...
+//	}
+func (o *Observer) UnregisterMetric(name MetricName) error {
+	if o.meter == nil || o.metricCollectorManager == nil {
+		return ErrMeterUnconfigured
+	}
+
+	return o.metricCollectorManager.unregister(name)
+}
+
 // Context-aware metric recording functions.
 // These functions extract trace_id and span_id from context automatically.
 
 // RecordCounterWithCtx increments a counter by the given value.
 // Counter values must be non-negative.
+// If ctx has a sampled span, the value carries an exemplar with trace/span IDs of the span.
 //
 // Example:
 //
 //	observer.RecordCounterWithCtx(ctx, "requests", 1, map[string]any{"method": "GET"})
 func (o *Observer) RecordCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
-	if o.meter == nil {
+	o.RecordCounterAttrs(ctx, name, value, mapToAttribute(metricAttrs)...)
+}
+
+// RecordCounterAttrs is like RecordCounterWithCtx but takes pre-computed attributes, skipping conversion of attribute map.
+// Build attributes once for stable attribute sets on hot paths, attrs is not modified.
+//
+// Example:
+//
+//	getAttrs := []attribute.KeyValue{attribute.String("method", "GET")}
+//	observer.RecordCounterAttrs(ctx, "requests", 1, getAttrs...)
+func (o *Observer) RecordCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+	if o.meter == nil || o.metricCollectorManager == nil {
 		stdLog.Printf("[error] Failed to use Meter: %v", ErrMeterUnconfigured)
 		return
 	}
 
+	o.metricCollectorManager.mu.RLock()
 	counter, ok := o.metricCollectorManager.counters[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
 	if !ok {
 		stdLog.Printf("[error] Failed to record Counter '%s': Not found", name)
 		return
@@ -345,92 +889,167 @@ func (o *Observer) RecordCounterWithCtx(ctx context.Context, name MetricName, va
 		return
 	}
 
-	attrs := mapToAttribute(metricAttrs)
+	attrs = o.metricCollectorManager.filterAttrs(name, attrs)
+	attrs = o.metricCollectorManager.addIdentityAttrs(ctx, name, attrs)
+	if !o.metricCollectorManager.checkAttrCardinality(name, attrs) {
+		return
+	}
 	counter.Add(ctx, value, metric.WithAttributes(attrs...))
 }
 
 // RecordUpDownCounterWithCtx adds the value to an up-down counter.
 // Value can be positive (increment) or negative (decrement).
+// If ctx has a sampled span, the value carries an exemplar with trace/span IDs of the span.
 //
 // Example:
 //
 //	observer.RecordUpDownCounterWithCtx(ctx, "connections", 1, map[string]any{"type": "websocket"})
 //	observer.RecordUpDownCounterWithCtx(ctx, "connections", -1, map[string]any{"type": "websocket"})
 func (o *Observer) RecordUpDownCounterWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
-	if o.meter == nil {
+	o.RecordUpDownCounterAttrs(ctx, name, value, mapToAttribute(metricAttrs)...)
+}
+
+// RecordUpDownCounterAttrs is like RecordUpDownCounterWithCtx but takes pre-computed attributes, skipping conversion of attribute map.
+// Build attributes once for stable attribute sets on hot paths, attrs is not modified.
+//
+// Example:
+//
+//	wsAttrs := []attribute.KeyValue{attribute.String("type", "websocket")}
+//	observer.RecordUpDownCounterAttrs(ctx, "connections", 1, wsAttrs...)
+func (o *Observer) RecordUpDownCounterAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+	if o.meter == nil || o.metricCollectorManager == nil {
 		stdLog.Printf("[error] Failed to use Meter: %v", ErrMeterUnconfigured)
 		return
 	}
 
+	o.metricCollectorManager.mu.RLock()
 	upDownCounter, ok := o.metricCollectorManager.upDownCounters[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
 	if !ok {
 		stdLog.Printf("[error] Failed to record UpDownCounter '%s': Not found", name)
 		return
 	}
 
-	attrs := mapToAttribute(metricAttrs)
+	attrs = o.metricCollectorManager.filterAttrs(name, attrs)
+	attrs = o.metricCollectorManager.addIdentityAttrs(ctx, name, attrs)
+	if !o.metricCollectorManager.checkAttrCardinality(name, attrs) {
+		return
+	}
 	upDownCounter.Add(ctx, value, metric.WithAttributes(attrs...))
 }
 
 // RecordHistogramWithCtx records a value in a histogram.
 // Histograms aggregate value distributions (e.g., latency percentiles).
+// If ctx has a sampled span, the value carries an exemplar with trace/span IDs of the span.
 //
 // Example:
 //
 //	observer.RecordHistogramWithCtx(ctx, "latency", 123.45, map[string]any{"endpoint": "/api/users"})
 func (o *Observer) RecordHistogramWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any) {
-	if o.meter == nil {
+	o.RecordHistogramAttrs(ctx, name, value, mapToAttribute(metricAttrs)...)
+}
+
+// RecordHistogramAttrs is like RecordHistogramWithCtx but takes pre-computed attributes, skipping conversion of attribute map.
+// Build attributes once for stable attribute sets on hot paths, attrs is not modified.
+//
+// Example:
+//
+//	usersAttrs := []attribute.KeyValue{attribute.String("endpoint", "/api/users")}
+//	observer.RecordHistogramAttrs(ctx, "latency", 123.45, usersAttrs...)
+func (o *Observer) RecordHistogramAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue) {
+	if o.meter == nil || o.metricCollectorManager == nil {
 		stdLog.Printf("[error] Failed to use Meter: %v", ErrMeterUnconfigured)
 		return
 	}
 
+	o.metricCollectorManager.mu.RLock()
 	histogram, ok := o.metricCollectorManager.histograms[name.Get()]
+	stat := o.metricCollectorManager.histogramStats[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
 	if !ok {
 		stdLog.Printf("[error] Failed to record Histogram '%s': Not found", name)
 		return
 	}
 
-	attrs := mapToAttribute(metricAttrs)
+	attrs = o.metricCollectorManager.filterAttrs(name, attrs)
+	attrs = o.metricCollectorManager.addIdentityAttrs(ctx, name, attrs)
+	if !o.metricCollectorManager.checkAttrCardinality(name, attrs) {
+		return
+	}
 	histogram.Record(ctx, value, metric.WithAttributes(attrs...))
+
+	if stat != nil {
+		stat.mu.Lock()
+		stat.count++
+		stat.sum += value
+		stat.mu.Unlock()
+	}
 }
 
-// Context-less metric recording functions.
-// Use these when context is not available.
+// HistogramSnapshot returns running count and sum of values recorded to a histogram since it was registered,
+// e.g. for computing a local average without a metric backend.
+// Histogram must be registered with LocalStats, otherwise both are zero. Exported histogram aggregation is not affected.
+//
+// Example:
+//
+//	count, sum := observer.HistogramSnapshot("latency")
+//	if count > 0 {
+//		avg := sum / float64(count)
+//	}
+func (o *Observer) HistogramSnapshot(name MetricName) (int64, float64) {
+	if o.metricCollectorManager == nil {
+		return 0, 0
+	}
 
-// RecordCounter increments a counter without trace context (callback: RecordCounterWithCtx)
-func (o *Observer) RecordCounter(name MetricName, value int64, metricAttrs map[string]any) {
-	o.RecordCounterWithCtx(context.Background(), name, value, metricAttrs)
-}
+	o.metricCollectorManager.mu.RLock()
+	stat, ok := o.metricCollectorManager.histogramStats[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
+	if !ok {
+		return 0, 0
+	}
 
-// RecordUpDownCounter updates an up-down counter without trace context (callback: RecordUpDownCounterWithCtx)
-func (o *Observer) RecordUpDownCounter(name MetricName, value int64, metricAttrs map[string]any) {
-	o.RecordUpDownCounterWithCtx(context.Background(), name, value, metricAttrs)
+	stat.mu.Lock()
+	defer stat.mu.Unlock()
+	return stat.count, stat.sum
 }
 
-// RecordHistogram records a histogram value without trace context (callback: RecordHistogramWithCtx)
-func (o *Observer) RecordHistogram(name MetricName, value float64, metricAttrs map[string]any) {
-	o.RecordHistogramWithCtx(context.Background(), name, value, metricAttrs)
+// RecordGaugeWithCtx updates a gauge to the given value.
+// Gauges represent current state (e.g., CPU usage, queue size).
+// Gauge values are observed asynchronously on collection, context is kept for consistency with other metric types.
+//
+// Example:
+//
+//	observer.RecordGaugeWithCtx(ctx, "memory_usage", 75.5, map[string]any{"host": "server-1"})
+func (o *Observer) RecordGaugeWithCtx(ctx context.Context, name MetricName, value float64, metricAttrs map[string]any) {
+	o.RecordGaugeAttrs(ctx, name, value, mapToAttribute(metricAttrs)...)
 }
 
-// RecordGauge updates a gauge to the given value.
-// Gauges represent current state (e.g., CPU usage, queue size).
+// RecordGaugeAttrs is like RecordGaugeWithCtx but takes pre-computed attributes, skipping conversion of attribute map.
+// Build attributes once for stable attribute sets on hot paths, attrs is not modified.
 //
 // Example:
 //
-//	observer.RecordGauge("memory_usage", 75.5, map[string]any{"host": "server-1"})
-func (o *Observer) RecordGauge(name MetricName, value float64, metricAttrs map[string]any) {
-	if o.meter == nil {
+//	hostAttrs := []attribute.KeyValue{attribute.String("host", "server-1")}
+//	observer.RecordGaugeAttrs(ctx, "memory_usage", 75.5, hostAttrs...)
+func (o *Observer) RecordGaugeAttrs(ctx context.Context, name MetricName, value float64, attrs ...attribute.KeyValue) {
+	if o.meter == nil || o.metricCollectorManager == nil {
 		stdLog.Printf("[error] Failed to use Meter: %v", ErrMeterUnconfigured)
 		return
 	}
 
+	o.metricCollectorManager.mu.RLock()
 	gaugeState, ok := o.metricCollectorManager.gauges[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
 	if !ok {
 		stdLog.Printf("[error] Failed to record Gauge '%s': Not found", name)
 		return
 	}
 
-	attrs := mapToAttribute(metricAttrs)
+	attrs = o.metricCollectorManager.filterAttrs(name, attrs)
+	attrs = o.metricCollectorManager.addIdentityAttrs(ctx, name, attrs)
+	if !o.metricCollectorManager.checkAttrCardinality(name, attrs) {
+		return
+	}
 	key := hashAttrs(attrs)
 
 	gaugeState.mu.Lock()
@@ -441,14 +1060,98 @@ func (o *Observer) RecordGauge(name MetricName, value float64, metricAttrs map[s
 		gaugeState.currentVals[key] = &gaugeValue{}
 	}
 	gaugeState.currentVals[key].value = value
-	gaugeState.currentVals[key].attrs = attrs
+	gaugeState.currentVals[key].attrs = slices.Clone(attrs)
+	gaugeState.currentVals[key].updatedAt = time.Now()
+}
+
+// RecordIntGaugeWithCtx updates an int gauge to the given value.
+// Int gauge must be registered with METRIC_VALUE_KIND_INT64 value kind.
+//
+// Example:
+//
+//	observer.RecordIntGaugeWithCtx(ctx, "queue_depth", 42, map[string]any{"queue": "default"})
+func (o *Observer) RecordIntGaugeWithCtx(ctx context.Context, name MetricName, value int64, metricAttrs map[string]any) {
+	o.RecordIntGaugeAttrs(ctx, name, value, mapToAttribute(metricAttrs)...)
+}
+
+// RecordIntGaugeAttrs is like RecordIntGaugeWithCtx but takes pre-computed attributes, skipping conversion of attribute map.
+// Build attributes once for stable attribute sets on hot paths, attrs is not modified.
+//
+// Example:
+//
+//	queueAttrs := []attribute.KeyValue{attribute.String("queue", "default")}
+//	observer.RecordIntGaugeAttrs(ctx, "queue_depth", 42, queueAttrs...)
+func (o *Observer) RecordIntGaugeAttrs(ctx context.Context, name MetricName, value int64, attrs ...attribute.KeyValue) {
+	if o.meter == nil || o.metricCollectorManager == nil {
+		stdLog.Printf("[error] Failed to use Meter: %v", ErrMeterUnconfigured)
+		return
+	}
+
+	o.metricCollectorManager.mu.RLock()
+	gaugeState, ok := o.metricCollectorManager.intGauges[name.Get()]
+	o.metricCollectorManager.mu.RUnlock()
+	if !ok {
+		stdLog.Printf("[error] Failed to record IntGauge '%s': Not found", name)
+		return
+	}
+
+	attrs = o.metricCollectorManager.filterAttrs(name, attrs)
+	attrs = o.metricCollectorManager.addIdentityAttrs(ctx, name, attrs)
+	if !o.metricCollectorManager.checkAttrCardinality(name, attrs) {
+		return
+	}
+	key := hashAttrs(attrs)
+
+	gaugeState.mu.Lock()
+	defer gaugeState.mu.Unlock()
+
+	// Update gauge value
+	if _, ok := gaugeState.currentVals[key]; !ok {
+		gaugeState.currentVals[key] = &intGaugeValue{}
+	}
+	gaugeState.currentVals[key].value = value
+	gaugeState.currentVals[key].attrs = slices.Clone(attrs)
 	gaugeState.currentVals[key].updatedAt = time.Now()
 }
 
+// Context-less metric recording functions.
+// Use these when context is not available.
+
+// RecordCounter increments a counter without trace context (callback: RecordCounterWithCtx)
+func (o *Observer) RecordCounter(name MetricName, value int64, metricAttrs map[string]any) {
+	o.RecordCounterWithCtx(context.Background(), name, value, metricAttrs)
+}
+
+// RecordUpDownCounter updates an up-down counter without trace context (callback: RecordUpDownCounterWithCtx)
+func (o *Observer) RecordUpDownCounter(name MetricName, value int64, metricAttrs map[string]any) {
+	o.RecordUpDownCounterWithCtx(context.Background(), name, value, metricAttrs)
+}
+
+// RecordHistogram records a histogram value without trace context (callback: RecordHistogramWithCtx)
+func (o *Observer) RecordHistogram(name MetricName, value float64, metricAttrs map[string]any) {
+	o.RecordHistogramWithCtx(context.Background(), name, value, metricAttrs)
+}
+
+// RecordGauge updates a gauge without trace context (callback: RecordGaugeWithCtx)
+func (o *Observer) RecordGauge(name MetricName, value float64, metricAttrs map[string]any) {
+	o.RecordGaugeWithCtx(context.Background(), name, value, metricAttrs)
+}
+
+// RecordIntGauge updates an int gauge without trace context (callback: RecordIntGaugeWithCtx)
+func (o *Observer) RecordIntGauge(name MetricName, value int64, metricAttrs map[string]any) {
+	o.RecordIntGaugeWithCtx(context.Background(), name, value, metricAttrs)
+}
+
+// hashAttrs returns a key of attribute set regardless of attribute order, attrs is not modified.
 func hashAttrs(attrs []attribute.KeyValue) string {
-	sort.Slice(attrs, func(i, j int) bool {
-		return attrs[i].Key < attrs[j].Key
-	})
+	compareKey := func(a, b attribute.KeyValue) int {
+		return strings.Compare(string(a.Key), string(b.Key))
+	}
+	// Sort a copy only if needed, attrs may be shared by caller (e.g. Record*Attrs)
+	if !slices.IsSortedFunc(attrs, compareKey) {
+		attrs = slices.Clone(attrs)
+		slices.SortFunc(attrs, compareKey)
+	}
 
 	b := strings.Builder{}
 	for _, a := range attrs {
@@ -0,0 +1,71 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// WithPrometheusExporter serves all registered metrics for Prometheus scraping at http://<addr>/metrics.
+// It runs alongside OTLP push of Meter, so WithMeter is required.
+//
+// Example:
+//
+//	observer, err := otel.NewOtelObserver(
+//	    otel.WithMeter(&otel.MeterConfig{...}),
+//	    otel.WithPrometheusExporter(":9464"),
+//	)
+func WithPrometheusExporter(addr string) ObserverOption {
+	return observerOptionFunc(func(o *Observer) error {
+		if addr == "" {
+			return errors.New("invalid Prometheus exporter config: address is required")
+		}
+
+		o.prometheusAddr = addr
+		return nil
+	})
+}
+
+// initPrometheusExporter starts HTTP server for Prometheus scraping, returns Prometheus reader for Meter and a cleanup function.
+func initPrometheusExporter(addr string) (sdkmetric.Reader, func(ctx context.Context), error) {
+	// Use own registry, so only metrics of Meter are exposed
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Prometheus exporter for Meter: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on '%s' for Prometheus exporter: %v", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			stdLog.Printf("[error] Prometheus exporter server stopped: %v", err)
+		}
+	}()
+
+	shutdown := func(ctx context.Context) {
+		if err := server.Shutdown(ctx); err != nil {
+			stdLog.Printf("[error] Failed to shut down Prometheus exporter server: %v", err)
+		}
+	}
+
+	return exporter, shutdown, nil
+}
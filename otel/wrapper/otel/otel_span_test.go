@@ -0,0 +1,41 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanStartedFromParentContextIsSibling(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer tracerProvider.Shutdown(context.Background())
+	observer := &Observer{tracer: tracerProvider.Tracer("test")}
+
+	rootCtx, rootSpan := observer.NewSpan(context.Background(), "root")
+	_, firstSpan := observer.NewSpan(rootCtx, "first")
+	_, siblingSpan := observer.NewSpan(firstSpan.ParentContext(), "sibling")
+	_, childSpan := observer.NewSpan(firstSpan.Context(), "child")
+
+	rootSpanID := trace.SpanContextFromContext(rootSpan.Context()).SpanID()
+	firstSpanID := trace.SpanContextFromContext(firstSpan.Context()).SpanID()
+
+	for _, span := range []*Span{childSpan, siblingSpan, firstSpan, rootSpan} {
+		span.Done()
+	}
+
+	parentSpanIDs := make(map[string]trace.SpanID)
+	for _, span := range recorder.Ended() {
+		parentSpanIDs[span.Name()] = span.Parent().SpanID()
+	}
+
+	if parentSpanIDs["sibling"] != rootSpanID {
+		t.Errorf("parent of span started from ParentContext = %s, expected root %s", parentSpanIDs["sibling"], rootSpanID)
+	}
+	if parentSpanIDs["child"] != firstSpanID {
+		t.Errorf("parent of span started from Context = %s, expected first %s", parentSpanIDs["child"], firstSpanID)
+	}
+}
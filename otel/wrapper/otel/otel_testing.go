@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestingT is the subset of testing.TB used by assertion helpers, *testing.T and *testing.B satisfy it.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertTraceContinuity reports a test error when the child context does not continue the trace of the parent context.
+// Use it to guard async boundaries (goroutines, pub/sub, cache) against losing trace context.
+//
+// Example:
+//
+//	ctx, span := observer.NewSpan(context.Background(), "Parent")
+//	childCtx := carrier.ExtractContext()
+//	otel.AssertTraceContinuity(t, ctx, childCtx)
+func AssertTraceContinuity(t TestingT, parentCtx context.Context, childCtx context.Context) bool {
+	t.Helper()
+
+	parentSpanCtx := trace.SpanContextFromContext(parentCtx)
+	if !parentSpanCtx.IsValid() {
+		t.Errorf("parent context has no valid span context")
+		return false
+	}
+
+	childSpanCtx := trace.SpanContextFromContext(childCtx)
+	if !childSpanCtx.IsValid() {
+		t.Errorf("child context has no valid span context, expected trace_id %s", parentSpanCtx.TraceID())
+		return false
+	}
+
+	if childSpanCtx.TraceID() != parentSpanCtx.TraceID() {
+		t.Errorf("trace is broken: child trace_id %s, expected trace_id %s", childSpanCtx.TraceID(), parentSpanCtx.TraceID())
+		return false
+	}
+	return true
+}
+
+// AssertCarrierRoundTrip reports a test error when the Trace Carrier does not survive an extract/export round trip.
+// The Text map propagator must be configured (done by WithTracer option) before calling this function.
+//
+// Example:
+//
+//	carrier := otel.ExportTraceCarrier(ctx)
+//	otel.AssertCarrierRoundTrip(t, carrier)
+func AssertCarrierRoundTrip(t TestingT, traceCarrier TraceCarrier) bool {
+	t.Helper()
+
+	spanCtx := trace.SpanContextFromContext(traceCarrier.ExtractContext())
+	if !spanCtx.IsValid() {
+		t.Errorf("trace carrier has no valid span context: %v", traceCarrier)
+		return false
+	}
+
+	roundTripSpanCtx := trace.SpanContextFromContext(ExportTraceCarrier(traceCarrier.ExtractContext()).ExtractContext())
+	if roundTripSpanCtx.TraceID() != spanCtx.TraceID() || roundTripSpanCtx.SpanID() != spanCtx.SpanID() {
+		t.Errorf("span context is not preserved: got trace_id %s span_id %s, expected trace_id %s span_id %s",
+			roundTripSpanCtx.TraceID(), roundTripSpanCtx.SpanID(), spanCtx.TraceID(), spanCtx.SpanID())
+		return false
+	}
+	if roundTripSpanCtx.TraceFlags() != spanCtx.TraceFlags() {
+		t.Errorf("trace flags are not preserved: got %s, expected %s", roundTripSpanCtx.TraceFlags(), spanCtx.TraceFlags())
+		return false
+	}
+	return true
+}
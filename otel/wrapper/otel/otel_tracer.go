@@ -2,15 +2,15 @@ package otel
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.18.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -20,44 +20,93 @@ type TracerConfig struct {
 	ServiceVersion string            // Version of the service
 	EndPoint       string            // OTLP endpoint for exporting tracing data
 	Insecure       bool              // Allow HTTP schema, instead of HTTPS
-	HttpHeader     map[string]string // Additional HTTP headers
-}
+	HttpHeader     map[string]string // Additional HTTP headers (gRPC metadata when using gRPC protocol)
+	Protocol       ExportProtocol    // OTLP protocol for exporting (default: EXPORT_PROTOCOL_HTTP)
 
-// initTracer initializes the Trace, returns Tracer and a cleanup function.
-// Spans are exported using OTLP HTTP protocol with batch processing.
-func initTracer(config *TracerConfig) (trace.Tracer, func(ctx context.Context)) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ResourceAttributes map[string]string // Custom resource attributes added to all exported data, e.g. "deployment.environment", "k8s.pod.name"
 
-	opts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(config.EndPoint),
+	SampleRatio float64 // Ratio of sampled root traces in (0, 1), child spans follow parent decision (0 or >= 1: sample all)
+
+	MaxQueueSize       int           // Max number of spans buffered for export, spans are dropped when full (0: SDK default 2048)
+	BatchTimeout       time.Duration // Max delay before exporting buffered spans (0: SDK default 5s)
+	MaxExportBatchSize int           // Max number of spans per export (0: SDK default 512)
+}
+
+// Validate checks required fields and values of TracerConfig.
+func (config *TracerConfig) Validate() error {
+	if config.ServiceName == "" {
+		return errors.New("service name is required")
+	}
+	if config.EndPoint == "" {
+		return errors.New("end point is required")
 	}
-	if config.Insecure {
-		opts = append(opts, otlptracehttp.WithInsecure())
+	if err := config.Protocol.validate(); err != nil {
+		return err
 	}
-	if len(config.HttpHeader) > 0 {
-		opts = append(opts, otlptracehttp.WithHeaders(config.HttpHeader))
+	if err := validateResourceAttrs(config.ResourceAttributes); err != nil {
+		return err
 	}
+	if config.SampleRatio < 0 {
+		return fmt.Errorf("sample ratio %v must be non-negative", config.SampleRatio)
+	}
+	if config.MaxQueueSize < 0 || config.BatchTimeout < 0 || config.MaxExportBatchSize < 0 {
+		return errors.New("batch processor settings must be non-negative")
+	}
+	if config.MaxQueueSize > 0 && config.MaxExportBatchSize > config.MaxQueueSize {
+		return fmt.Errorf("max export batch size %d must not exceed max queue size %d", config.MaxExportBatchSize, config.MaxQueueSize)
+	}
+	return nil
+}
+
+// initTracer initializes the Trace, returns Tracer and a cleanup function.
+// Spans are exported using OTLP HTTP (or gRPC) protocol with batch processing.
+func initTracer(config *TracerConfig, opts tracerOptions, health *exportHealth) (trace.Tracer, func(ctx context.Context), error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Create OTLP HTTP exporter for sending traces
-	exporter, err := otlptracehttp.New(ctx, opts...)
+	// Create OTLP exporter for sending traces
+	exporter, err := newTraceExporter(ctx, config)
 	if err != nil {
-		stdLog.Fatalf("[error] Failed to create exporter for Tracer: %v", err)
+		return nil, nil, fmt.Errorf("failed to create exporter for Tracer: %v", err)
 	}
 
 	// Create resource with service metadata
-	resource := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceName(config.ServiceName),
-		semconv.ServiceVersion(config.ServiceVersion),
-		attribute.String("host.ip", getLocalIP()),
-	)
+	resource := newResource(config.ServiceName, config.ServiceVersion, config.ResourceAttributes)
 
 	// Create Tracer provider with batch span processor for efficient export
-	tracerProvider := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	batcherOpts := make([]sdktrace.BatchSpanProcessorOption, 0)
+	if config.MaxQueueSize > 0 {
+		batcherOpts = append(batcherOpts, sdktrace.WithMaxQueueSize(config.MaxQueueSize))
+	}
+	if config.BatchTimeout > 0 {
+		batcherOpts = append(batcherOpts, sdktrace.WithBatchTimeout(config.BatchTimeout))
+	}
+	if config.MaxExportBatchSize > 0 {
+		batcherOpts = append(batcherOpts, sdktrace.WithMaxExportBatchSize(config.MaxExportBatchSize))
+	}
+	var spanProcessor sdktrace.SpanProcessor = sdktrace.NewBatchSpanProcessor(&exportHealthSpanExporter{exporter, health.track(exportSignalTraces)}, batcherOpts...)
+	var sampler sdktrace.Sampler
+	erroredTraces.Store(nil)
+	if config.SampleRatio > 0 && config.SampleRatio < 1 {
+		// Parent based sampler keeps distributed traces complete across services
+		sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))
+
+		// Error sampling only matters when some traces are not sampled
+		if opts.errorSampling {
+			set := newErroredTraceSet()
+			sampler = &errorSampler{base: sampler, set: set}
+			spanProcessor = &errorSamplingProcessor{next: spanProcessor, set: set}
+			erroredTraces.Store(set)
+		}
+	}
+	tracerProviderOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(spanProcessor),
 		sdktrace.WithResource(resource),
-	)
+	}
+	if sampler != nil {
+		tracerProviderOpts = append(tracerProviderOpts, sdktrace.WithSampler(sampler))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(tracerProviderOpts...)
 
 	otel.SetTracerProvider(tracerProvider)
 
@@ -79,5 +128,41 @@ func initTracer(config *TracerConfig) (trace.Tracer, func(ctx context.Context))
 	}
 
 	// Return Tracer and cleanup function for Tracer
-	return tracer, shutdown
+	return tracer, shutdown, nil
+}
+
+// newTraceExporter creates OTLP exporter for Tracer by the configured protocol.
+func newTraceExporter(ctx context.Context, config *TracerConfig) (sdktrace.SpanExporter, error) {
+	switch config.Protocol {
+	case "", EXPORT_PROTOCOL_HTTP:
+		{
+			opts := []otlptracehttp.Option{
+				otlptracehttp.WithEndpoint(config.EndPoint),
+			}
+			if config.Insecure {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlptracehttp.WithHeaders(config.HttpHeader))
+			}
+			return otlptracehttp.New(ctx, opts...)
+		}
+	case EXPORT_PROTOCOL_GRPC:
+		{
+			opts := []otlptracegrpc.Option{
+				otlptracegrpc.WithEndpoint(config.EndPoint),
+			}
+			if config.Insecure {
+				opts = append(opts, otlptracegrpc.WithInsecure())
+			}
+			if len(config.HttpHeader) > 0 {
+				opts = append(opts, otlptracegrpc.WithHeaders(config.HttpHeader))
+			}
+			return otlptracegrpc.New(ctx, opts...)
+		}
+	default:
+		{
+			return nil, fmt.Errorf("export protocol '%s' is not valid", config.Protocol)
+		}
+	}
 }